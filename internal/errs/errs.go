@@ -24,4 +24,6 @@ var (
 	ErrTaskIdMissingMatch   = errors.New("task ID mismatch in agent response")
 	ErrBadTaskId            = errors.New("bad task id: task id in request does not match the task object")
 	ErrNilMessage           = errors.New("message is nil")
+	ErrPermissionDenied     = errors.New("permission denied")
+	ErrShuttingDown         = errors.New("server is shutting down")
 )
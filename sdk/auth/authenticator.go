@@ -0,0 +1,137 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+var (
+	// ErrMissingCredentials is returned when the request carries no credentials for the scheme.
+	ErrMissingCredentials = errors.New("missing credentials")
+	// ErrInvalidCredentials is returned when the credentials of the request are rejected.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUnsupportedScheme is returned when an authenticator is used with a scheme it does not handle.
+	ErrUnsupportedScheme = errors.New("unsupported security scheme")
+)
+
+// Authenticator validates the credentials a request carries for a security scheme.
+type Authenticator interface {
+	// Authenticate returns the user identified by the credentials of the request.
+	// Scopes are the ones the security requirement lists for the scheme.
+	// It returns ErrMissingCredentials when the request carries none.
+	Authenticate(r *http.Request, scheme types.SecurityScheme, scopes []string) (User, error)
+}
+
+// AuthenticatorFunc is a function type for Authenticator.
+type AuthenticatorFunc func(r *http.Request, scheme types.SecurityScheme, scopes []string) (User, error)
+
+func (fn AuthenticatorFunc) Authenticate(r *http.Request, scheme types.SecurityScheme, scopes []string) (User, error) {
+	return fn(r, scheme, scopes)
+}
+
+// APIKeyValidator returns the user owning the API key, or ErrInvalidCredentials.
+type APIKeyValidator func(ctx context.Context, key string) (User, error)
+
+// TokenValidator returns the user the bearer token was issued to, or ErrInvalidCredentials.
+// It must check that the token grants the given scopes.
+type TokenValidator func(ctx context.Context, token string, scopes []string) (User, error)
+
+// PasswordValidator returns the user with the given name and password, or ErrInvalidCredentials.
+type PasswordValidator func(ctx context.Context, username, password string) (User, error)
+
+// NewAPIKeyAuthenticator creates an Authenticator for API key schemes, reading the
+// key from the header, query parameter or cookie that the scheme declares.
+func NewAPIKeyAuthenticator(validate APIKeyValidator) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request, scheme types.SecurityScheme, scopes []string) (User, error) {
+		s, ok := scheme.(types.APIKeySecurityScheme)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s for api key authenticator", ErrUnsupportedScheme, scheme.GetType())
+		}
+		var key string
+		switch s.In {
+		case types.InHeader:
+			key = r.Header.Get(s.Name)
+		case types.InQuery:
+			key = r.URL.Query().Get(s.Name)
+		case types.InCookie:
+			if cookie, err := r.Cookie(s.Name); err == nil {
+				key = cookie.Value
+			}
+		default:
+			return nil, fmt.Errorf("%w: api key in %q", ErrUnsupportedScheme, s.In)
+		}
+		if key == "" {
+			return nil, ErrMissingCredentials
+		}
+		return validate(r.Context(), key)
+	})
+}
+
+// NewBearerAuthenticator creates an Authenticator for bearer tokens sent in the
+// Authorization header, as used by http bearer, oauth2 and openIdConnect schemes.
+func NewBearerAuthenticator(validate TokenValidator) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request, scheme types.SecurityScheme, scopes []string) (User, error) {
+		switch s := scheme.(type) {
+		case types.HTTPAuthSecurityScheme:
+			if !strings.EqualFold(s.Scheme, "bearer") {
+				return nil, fmt.Errorf("%w: http scheme %q for bearer authenticator", ErrUnsupportedScheme, s.Scheme)
+			}
+		case types.OAuth2SecurityScheme, types.OpenIdConnectSecurityScheme:
+		default:
+			return nil, fmt.Errorf("%w: %s for bearer authenticator", ErrUnsupportedScheme, scheme.GetType())
+		}
+		token, ok := authorization(r, "Bearer")
+		if !ok {
+			return nil, ErrMissingCredentials
+		}
+		return validate(r.Context(), token, scopes)
+	})
+}
+
+// NewBasicAuthenticator creates an Authenticator for the http basic scheme.
+func NewBasicAuthenticator(validate PasswordValidator) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request, scheme types.SecurityScheme, scopes []string) (User, error) {
+		s, ok := scheme.(types.HTTPAuthSecurityScheme)
+		if !ok || !strings.EqualFold(s.Scheme, "basic") {
+			return nil, fmt.Errorf("%w: %s for basic authenticator", ErrUnsupportedScheme, scheme.GetType())
+		}
+		if _, ok := authorization(r, "Basic"); !ok {
+			return nil, ErrMissingCredentials
+		}
+		username, password, ok := r.BasicAuth()
+		if !ok {
+			return nil, ErrInvalidCredentials
+		}
+		return validate(r.Context(), username, password)
+	})
+}
+
+// authorization returns the credentials of the Authorization header if it uses the given scheme.
+func authorization(r *http.Request, scheme string) (string, bool) {
+	value := r.Header.Get("Authorization")
+	prefix, credentials, ok := strings.Cut(value, " ")
+	if !ok || !strings.EqualFold(prefix, scheme) {
+		return "", false
+	}
+	credentials = strings.TrimSpace(credentials)
+	return credentials, credentials != ""
+}
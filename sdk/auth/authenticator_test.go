@@ -0,0 +1,135 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestAuthenticators(t *testing.T) {
+	apiKey := NewAPIKeyAuthenticator(func(ctx context.Context, key string) (User, error) {
+		if key != "valid" {
+			return nil, ErrInvalidCredentials
+		}
+		return BasicUser{Name: "key"}, nil
+	})
+	bearer := NewBearerAuthenticator(func(ctx context.Context, token string, scopes []string) (User, error) {
+		if token != "valid" {
+			return nil, ErrInvalidCredentials
+		}
+		return BasicUser{Name: "token"}, nil
+	})
+	basic := NewBasicAuthenticator(func(ctx context.Context, username, password string) (User, error) {
+		if password != "secret" {
+			return nil, ErrInvalidCredentials
+		}
+		return BasicUser{Name: username}, nil
+	})
+
+	testcases := []struct {
+		name          string
+		authenticator Authenticator
+		scheme        types.SecurityScheme
+		prepare       func(r *http.Request)
+		wantUser      string
+		wantErr       error
+	}{
+		{
+			name:          "api key in header",
+			authenticator: apiKey,
+			scheme:        types.APIKeySecurityScheme{In: types.InHeader, Name: "X-API-Key"},
+			prepare:       func(r *http.Request) { r.Header.Set("X-API-Key", "valid") },
+			wantUser:      "key",
+		},
+		{
+			name:          "api key in query",
+			authenticator: apiKey,
+			scheme:        types.APIKeySecurityScheme{In: types.InQuery, Name: "key"},
+			prepare:       func(r *http.Request) { r.URL.RawQuery = "key=valid" },
+			wantUser:      "key",
+		},
+		{
+			name:          "api key in cookie",
+			authenticator: apiKey,
+			scheme:        types.APIKeySecurityScheme{In: types.InCookie, Name: "session"},
+			prepare:       func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "session", Value: "valid"}) },
+			wantUser:      "key",
+		},
+		{
+			name:          "missing api key",
+			authenticator: apiKey,
+			scheme:        types.APIKeySecurityScheme{In: types.InHeader, Name: "X-API-Key"},
+			prepare:       func(r *http.Request) {},
+			wantErr:       ErrMissingCredentials,
+		},
+		{
+			name:          "bearer token for oauth2",
+			authenticator: bearer,
+			scheme:        types.OAuth2SecurityScheme{},
+			prepare:       func(r *http.Request) { r.Header.Set("Authorization", "Bearer valid") },
+			wantUser:      "token",
+		},
+		{
+			name:          "invalid bearer token",
+			authenticator: bearer,
+			scheme:        types.HTTPAuthSecurityScheme{Scheme: "bearer"},
+			prepare:       func(r *http.Request) { r.Header.Set("Authorization", "Bearer other") },
+			wantErr:       ErrInvalidCredentials,
+		},
+		{
+			name:          "bearer authenticator with basic scheme",
+			authenticator: bearer,
+			scheme:        types.HTTPAuthSecurityScheme{Scheme: "basic"},
+			prepare:       func(r *http.Request) { r.Header.Set("Authorization", "Bearer valid") },
+			wantErr:       ErrUnsupportedScheme,
+		},
+		{
+			name:          "basic credentials",
+			authenticator: basic,
+			scheme:        types.HTTPAuthSecurityScheme{Scheme: "Basic"},
+			prepare:       func(r *http.Request) { r.SetBasicAuth("alice", "secret") },
+			wantUser:      "alice",
+		},
+		{
+			name:          "malformed basic credentials",
+			authenticator: basic,
+			scheme:        types.HTTPAuthSecurityScheme{Scheme: "basic"},
+			prepare:       func(r *http.Request) { r.Header.Set("Authorization", "Basic !!!") },
+			wantErr:       ErrInvalidCredentials,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			tc.prepare(req)
+			user, err := tc.authenticator.Authenticate(req, tc.scheme, nil)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, user.IsAuthenticated())
+			assert.Equal(t, tc.wantUser, user.UserName())
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// CertificateMapper returns the user identified by a verified client certificate, or ErrInvalidCredentials.
+type CertificateMapper func(cert *x509.Certificate) (User, error)
+
+// CertificateUser is an authenticated User identified by a client certificate.
+type CertificateUser struct {
+	Name        string
+	Certificate *x509.Certificate
+}
+
+func (u CertificateUser) IsAuthenticated() bool {
+	return true
+}
+
+func (u CertificateUser) UserName() string {
+	return u.Name
+}
+
+// SubjectUser maps a certificate to a CertificateUser named after the common name of its
+// subject or, when it has none, its first DNS, URI or email subject alternative name.
+func SubjectUser(cert *x509.Certificate) (User, error) {
+	name := cert.Subject.CommonName
+	switch {
+	case name != "":
+	case len(cert.DNSNames) > 0:
+		name = cert.DNSNames[0]
+	case len(cert.URIs) > 0:
+		name = cert.URIs[0].String()
+	case len(cert.EmailAddresses) > 0:
+		name = cert.EmailAddresses[0]
+	default:
+		return nil, fmt.Errorf("%w: certificate has no subject name", ErrInvalidCredentials)
+	}
+	return CertificateUser{Name: name, Certificate: cert}, nil
+}
+
+// NewCertificateAuthenticator creates an Authenticator for the mutualTLS scheme. The client
+// certificate must have been verified during the TLS handshake, see handler.WithMutualTLS.
+// If mapper is nil, SubjectUser is used.
+func NewCertificateAuthenticator(mapper CertificateMapper) Authenticator {
+	if mapper == nil {
+		mapper = SubjectUser
+	}
+	return AuthenticatorFunc(func(r *http.Request, scheme types.SecurityScheme, scopes []string) (User, error) {
+		if _, ok := scheme.(types.MutualTLSSecurityScheme); !ok {
+			return nil, fmt.Errorf("%w: %s for certificate authenticator", ErrUnsupportedScheme, scheme.GetType())
+		}
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return nil, ErrMissingCredentials
+		}
+		if len(r.TLS.VerifiedChains) == 0 {
+			return nil, fmt.Errorf("%w: client certificate is not verified", ErrInvalidCredentials)
+		}
+		return mapper(r.TLS.VerifiedChains[0][0])
+	})
+}
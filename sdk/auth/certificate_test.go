@@ -0,0 +1,102 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestCertificateAuthenticator(t *testing.T) {
+	spiffe, err := url.Parse("spiffe://example.com/agent")
+	require.NoError(t, err)
+	verified := func(cert *x509.Certificate) *tls.ConnectionState {
+		return &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}, VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+
+	testcases := []struct {
+		name     string
+		scheme   types.SecurityScheme
+		tls      *tls.ConnectionState
+		wantUser string
+		wantErr  error
+	}{
+		{
+			name:     "subject common name",
+			scheme:   types.MutualTLSSecurityScheme{},
+			tls:      verified(&x509.Certificate{Subject: pkix.Name{CommonName: "agent-a"}, DNSNames: []string{"agent.example.com"}}),
+			wantUser: "agent-a",
+		},
+		{
+			name:     "dns name",
+			scheme:   types.MutualTLSSecurityScheme{},
+			tls:      verified(&x509.Certificate{DNSNames: []string{"agent.example.com"}}),
+			wantUser: "agent.example.com",
+		},
+		{
+			name:     "uri",
+			scheme:   types.MutualTLSSecurityScheme{},
+			tls:      verified(&x509.Certificate{URIs: []*url.URL{spiffe}}),
+			wantUser: "spiffe://example.com/agent",
+		},
+		{
+			name:    "no subject name",
+			scheme:  types.MutualTLSSecurityScheme{},
+			tls:     verified(&x509.Certificate{}),
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "unverified certificate",
+			scheme:  types.MutualTLSSecurityScheme{},
+			tls:     &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}},
+			wantErr: ErrInvalidCredentials,
+		},
+		{
+			name:    "no certificate",
+			scheme:  types.MutualTLSSecurityScheme{},
+			tls:     &tls.ConnectionState{},
+			wantErr: ErrMissingCredentials,
+		},
+		{
+			name:    "unsupported scheme",
+			scheme:  types.HTTPAuthSecurityScheme{Scheme: "bearer"},
+			wantErr: ErrUnsupportedScheme,
+		},
+	}
+
+	authenticator := NewCertificateAuthenticator(nil)
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.TLS = tc.tls
+			user, err := authenticator.Authenticate(r, tc.scheme, nil)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantUser, user.UserName())
+		})
+	}
+}
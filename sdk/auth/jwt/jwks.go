@@ -0,0 +1,341 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+)
+
+const (
+	defaultJWKSTTL          = time.Hour
+	defaultMinRefreshPeriod = time.Minute
+	defaultJWKSFetchTimeout = 30 * time.Second
+)
+
+// KeySource provides the keys that verify token signatures.
+type KeySource interface {
+	// Key returns the key identified by kid for the algorithm: an *rsa.PublicKey,
+	// an *ecdsa.PublicKey or a []byte secret. It returns ErrUnknownKey when there is none.
+	Key(ctx context.Context, kid, alg string) (any, error)
+}
+
+// KeySourceFunc is a function type for KeySource.
+type KeySourceFunc func(ctx context.Context, kid, alg string) (any, error)
+
+func (fn KeySourceFunc) Key(ctx context.Context, kid, alg string) (any, error) {
+	return fn(ctx, kid, alg)
+}
+
+// HMACSecret returns a KeySource of a single shared secret for HS256 tokens.
+func HMACSecret(secret []byte) KeySource {
+	return KeySourceFunc(func(ctx context.Context, kid, alg string) (any, error) {
+		return secret, nil
+	})
+}
+
+// JWKS is a KeySource backed by a JSON Web Key Set published at a URL.
+// The set is cached for a TTL, and fetched again before the TTL expires when a
+// token references an unknown key id, so keys can be rotated by the issuer.
+// Concurrent lookups share a single fetch, during which the cached keys remain usable.
+// The set is fetched at most once per min refresh period, whether the fetch succeeds or
+// not, so that an unavailable issuer is not called again for every token.
+type JWKS struct {
+	url              string
+	client           *http.Client
+	ttl              time.Duration
+	minRefreshPeriod time.Duration
+	fetchTimeout     time.Duration
+	now              func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]jsonWebKey
+	fetchedAt   time.Time    // Time of the last successful fetch
+	attemptedAt time.Time    // Time of the last fetch, successful or not
+	fetchErr    error        // Error of the last fetch, nil if it succeeded
+	refreshing  *refreshCall // fetch in progress, nil if none
+}
+
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+
+	key any
+}
+
+// NewJWKS creates a JWKS fetching the key set from the given URL.
+func NewJWKS(url string, opts ...JWKSOption) *JWKS {
+	j := &JWKS{
+		url:              url,
+		client:           http.DefaultClient,
+		ttl:              defaultJWKSTTL,
+		minRefreshPeriod: defaultMinRefreshPeriod,
+		fetchTimeout:     defaultJWKSFetchTimeout,
+		now:              time.Now,
+	}
+	for _, opt := range opts {
+		opt.Option(j)
+	}
+	return j
+}
+
+// Key returns the key with the given id, fetching the key set when it is stale or does not contain it.
+// A token without kid matches the only key of the set usable with its algorithm.
+func (j *JWKS) Key(ctx context.Context, kid, alg string) (any, error) {
+	j.mu.Lock()
+	now := j.now()
+	stale := j.keys == nil || now.Sub(j.fetchedAt) >= j.ttl
+	if !stale {
+		if key, err := j.lookup(kid, alg); err == nil {
+			j.mu.Unlock()
+			return key, err
+		}
+	}
+	if j.refreshing == nil && !j.attemptedAt.IsZero() && now.Sub(j.attemptedAt) < j.minRefreshPeriod {
+		// Fetched too recently to fetch again, use the keys of the last successful fetch
+		defer j.mu.Unlock()
+		if j.keys == nil {
+			return nil, j.fetchErr
+		}
+		return j.lookup(kid, alg)
+	}
+	call := j.startRefresh()
+	j.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if call.err != nil {
+		if j.keys == nil {
+			return nil, call.err
+		}
+		log.Warnf("failed to refresh jwks from %s, using cached keys: %v", j.url, call.err)
+	}
+	return j.lookup(kid, alg)
+}
+
+// startRefresh fetches the key set unless a fetch is already in progress, and returns the
+// call to wait for. The caller must hold the lock. The fetch is not bound to the context of
+// the caller, so that a canceled verification does not fail the ones sharing the fetch.
+func (j *JWKS) startRefresh() *refreshCall {
+	if j.refreshing != nil {
+		return j.refreshing
+	}
+	call := &refreshCall{done: make(chan struct{})}
+	j.refreshing = call
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), j.fetchTimeout)
+		keys, err := j.fetch(ctx)
+		cancel()
+
+		j.mu.Lock()
+		j.attemptedAt = j.now()
+		j.fetchErr = err
+		if err == nil {
+			j.keys = keys
+			j.fetchedAt = j.attemptedAt
+		}
+		j.refreshing = nil
+		j.mu.Unlock()
+
+		call.err = err
+		close(call.done)
+	}()
+	return call
+}
+
+func (j *JWKS) lookup(kid, alg string) (any, error) {
+	var found *jsonWebKey
+	for id, k := range j.keys {
+		if k.Alg != "" && k.Alg != alg {
+			continue
+		}
+		if kid != "" && id != kid {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: several keys match a token without kid", ErrUnknownKey)
+		}
+		found = &k
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: kid %q", ErrUnknownKey, kid)
+	}
+	return found.key, nil
+}
+
+// fetch retrieves the key set, keeping the keys usable to verify signatures.
+func (j *JWKS) fetch(ctx context.Context) (map[string]jsonWebKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: failed to construct request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwks: failed to decode key set: %w", err)
+	}
+
+	keys := make(map[string]jsonWebKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Warnf("skipping jwk %q: %v", k.Kid, err)
+			continue
+		}
+		k.key = key
+		keys[k.Kid] = k
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !key.Curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point is not on the curve")
+		}
+		return key, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// JWKSOption allows customizing JWKS via functional options.
+type JWKSOption interface {
+	Option(j *JWKS)
+}
+
+// JWKSOptionFunc is a function type for JWKSOption.
+type JWKSOptionFunc func(j *JWKS)
+
+func (fn JWKSOptionFunc) Option(j *JWKS) {
+	fn(j)
+}
+
+// WithJWKSClient sets the HTTP client used to fetch the key set.
+func WithJWKSClient(client *http.Client) JWKSOption {
+	return JWKSOptionFunc(func(j *JWKS) {
+		j.client = client
+	})
+}
+
+// WithJWKSTTL sets how long the fetched key set is used before it is fetched again.
+func WithJWKSTTL(ttl time.Duration) JWKSOption {
+	return JWKSOptionFunc(func(j *JWKS) {
+		j.ttl = ttl
+	})
+}
+
+// WithJWKSFetchTimeout sets how long a fetch of the key set may take.
+func WithJWKSFetchTimeout(timeout time.Duration) JWKSOption {
+	return JWKSOptionFunc(func(j *JWKS) {
+		j.fetchTimeout = timeout
+	})
+}
+
+// WithMinRefreshPeriod sets the minimum time between two fetches triggered by unknown key ids,
+// which bounds the load tokens signed with unknown keys can put on the issuer.
+func WithMinRefreshPeriod(period time.Duration) JWKSOption {
+	return JWKSOptionFunc(func(j *JWKS) {
+		j.minRefreshPeriod = period
+	})
+}
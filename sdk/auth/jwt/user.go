@@ -0,0 +1,110 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Claims are the claims of a verified token. Numbers are json.Number values.
+type Claims map[string]any
+
+// String returns the claim if it is a string.
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Subject returns the sub claim.
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Audience returns the aud claim, which may be a string or an array of strings.
+func (c Claims) Audience() []string {
+	return stringList(c["aud"])
+}
+
+func (c Claims) time(name string) (time.Time, bool) {
+	n, ok := c[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))), true
+}
+
+// scopes reads the space separated scope claim (RFC 8693), or the scp claim some issuers use instead.
+func (c Claims) scopes() []string {
+	if scope := c.String("scope"); scope != "" {
+		return strings.Fields(scope)
+	}
+	if scp, ok := c["scp"].(string); ok {
+		return strings.Fields(scp)
+	}
+	return stringList(c["scp"])
+}
+
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+// User is the auth.User of a verified token, identified by its subject.
+type User struct {
+	claims Claims
+	scopes []string
+}
+
+func (u *User) IsAuthenticated() bool {
+	return true
+}
+
+func (u *User) UserName() string {
+	return u.claims.Subject()
+}
+
+// Claims returns the claims of the token.
+func (u *User) Claims() Claims {
+	return u.claims
+}
+
+// Scopes returns the scopes granted by the token.
+func (u *User) Scopes() []string {
+	return slices.Clone(u.scopes)
+}
+
+// HasScope reports whether the token grants the scope.
+func (u *User) HasScope(scope string) bool {
+	return slices.Contains(u.scopes, scope)
+}
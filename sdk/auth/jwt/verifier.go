@@ -0,0 +1,268 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+)
+
+// Signing algorithms supported by the Verifier.
+const (
+	RS256 = "RS256"
+	ES256 = "ES256"
+	HS256 = "HS256"
+)
+
+// Errors returned by the Verifier. They all wrap auth.ErrInvalidCredentials.
+var (
+	ErrMalformedToken    = fmt.Errorf("%w: malformed token", auth.ErrInvalidCredentials)
+	ErrUnsupportedAlg    = fmt.Errorf("%w: unsupported signing algorithm", auth.ErrInvalidCredentials)
+	ErrUnknownKey        = fmt.Errorf("%w: unknown signing key", auth.ErrInvalidCredentials)
+	ErrInvalidSignature  = fmt.Errorf("%w: invalid signature", auth.ErrInvalidCredentials)
+	ErrTokenExpired      = fmt.Errorf("%w: token is expired", auth.ErrInvalidCredentials)
+	ErrMissingExpiration = fmt.Errorf("%w: token has no expiration", auth.ErrInvalidCredentials)
+	ErrTokenNotValidYet  = fmt.Errorf("%w: token is not valid yet", auth.ErrInvalidCredentials)
+	ErrInvalidIssuer     = fmt.Errorf("%w: invalid issuer", auth.ErrInvalidCredentials)
+	ErrInvalidAudience   = fmt.Errorf("%w: invalid audience", auth.ErrInvalidCredentials)
+	ErrInsufficientScope = fmt.Errorf("%w: insufficient scope", auth.ErrInvalidCredentials)
+)
+
+// Verifier verifies signed JWT access tokens (RFC 7519) and maps them to a User.
+type Verifier struct {
+	keys        KeySource
+	algorithms  []string
+	issuer      string
+	audience    string
+	leeway      time.Duration
+	optionalExp bool // Accepts tokens without an exp claim
+	now         func() time.Time
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// NewVerifier creates a Verifier checking signatures with the keys of the source.
+// By default RS256 and ES256 are accepted; use WithAlgorithms to accept HS256.
+// Tokens without an exp claim are rejected, unless WithOptionalExpiration is set.
+func NewVerifier(keys KeySource, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		keys:       keys,
+		algorithms: []string{RS256, ES256},
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt.Option(v)
+	}
+	return v
+}
+
+// Verify checks the signature, the time window, the issuer and the audience of the
+// token, and that it grants all the given scopes. It returns the User of the token.
+func (v *Verifier) Verify(ctx context.Context, token string, scopes []string) (*User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(v.algorithms, h.Alg) {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, h.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	key, err := v.keys.Key(ctx, h.Kid, h.Alg)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(h.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+
+	user := &User{claims: claims, scopes: claims.scopes()}
+	for _, scope := range scopes {
+		if !slices.Contains(user.scopes, scope) {
+			return nil, fmt.Errorf("%w: %q is required", ErrInsufficientScope, scope)
+		}
+	}
+	return user, nil
+}
+
+// Validate verifies the token like Verify. It is an auth.TokenValidator.
+func (v *Verifier) Validate(ctx context.Context, token string, scopes []string) (auth.User, error) {
+	user, err := v.Verify(ctx, token, scopes)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Authenticator returns an auth.Authenticator validating the bearer tokens of
+// the oauth2, openIdConnect and http bearer schemes with the Verifier.
+func (v *Verifier) Authenticator() auth.Authenticator {
+	return auth.NewBearerAuthenticator(v.Validate)
+}
+
+func (v *Verifier) validate(claims Claims) error {
+	now := v.now()
+	exp, ok := claims.time("exp")
+	if !ok && !v.optionalExp {
+		return ErrMissingExpiration
+	}
+	if ok && !now.Before(exp.Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+	if nbf, ok := claims.time("nbf"); ok && now.Add(v.leeway).Before(nbf) {
+		return ErrTokenNotValidYet
+	}
+	if v.issuer != "" && claims.String("iss") != v.issuer {
+		return fmt.Errorf("%w: %q", ErrInvalidIssuer, claims.String("iss"))
+	}
+	if v.audience != "" && !slices.Contains(claims.Audience(), v.audience) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+func verifySignature(alg string, key any, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+	switch alg {
+	case RS256:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: %s requires an rsa key", ErrUnknownKey, alg)
+		}
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) != nil {
+			return ErrInvalidSignature
+		}
+	case ES256:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: %s requires an ec key", ErrUnknownKey, alg)
+		}
+		if len(signature) != 64 {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return ErrInvalidSignature
+		}
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 {
+			return fmt.Errorf("%w: %s requires a secret", ErrUnknownKey, alg)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidSignature
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlg, alg)
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return ErrMalformedToken
+	}
+	return nil
+}
+
+// VerifierOption allows customizing Verifier via functional options.
+type VerifierOption interface {
+	Option(v *Verifier)
+}
+
+// VerifierOptionFunc is a function type for VerifierOption.
+type VerifierOptionFunc func(v *Verifier)
+
+func (fn VerifierOptionFunc) Option(v *Verifier) {
+	fn(v)
+}
+
+// WithIssuer requires the iss claim to be equal to the issuer.
+func WithIssuer(issuer string) VerifierOption {
+	return VerifierOptionFunc(func(v *Verifier) {
+		v.issuer = issuer
+	})
+}
+
+// WithAudience requires the aud claim to contain the audience.
+func WithAudience(audience string) VerifierOption {
+	return VerifierOptionFunc(func(v *Verifier) {
+		v.audience = audience
+	})
+}
+
+// WithAlgorithms sets the signing algorithms accepted by the verifier.
+func WithAlgorithms(algorithms ...string) VerifierOption {
+	return VerifierOptionFunc(func(v *Verifier) {
+		v.algorithms = algorithms
+	})
+}
+
+// WithOptionalExpiration accepts tokens without an exp claim, which never expire.
+// By default, they are rejected.
+func WithOptionalExpiration() VerifierOption {
+	return VerifierOptionFunc(func(v *Verifier) {
+		v.optionalExp = true
+	})
+}
+
+// WithLeeway sets the clock skew tolerated when checking exp and nbf.
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return VerifierOptionFunc(func(v *Verifier) {
+		v.leeway = leeway
+	})
+}
@@ -0,0 +1,384 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+)
+
+func encodeSegment(t *testing.T, v any) string {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func sign(t *testing.T, alg, kid string, key any, claims map[string]any) string {
+	input := encodeSegment(t, map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		require.NoError(t, err)
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": RS256,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+type jwksServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    []map[string]string
+	fetches atomic.Int32
+}
+
+func newJWKSServer(keys ...map[string]string) *jwksServer {
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": s.keys})
+	}))
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func TestVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := newJWKSServer(rsaJWK("rsa", rsaKey), ecJWK("ec", ecKey))
+	defer server.Close()
+
+	now := time.Now()
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":   "https://issuer.example.com",
+			"aud":   []string{"https://agent.example.com"},
+			"sub":   "alice",
+			"scope": "tasks.read tasks.write",
+			"exp":   now.Add(time.Hour).Unix(),
+			"nbf":   now.Add(-time.Minute).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	testcases := []struct {
+		name       string
+		token      string
+		scopes     []string
+		wantScopes []string
+		wantErr    error
+	}{
+		{
+			name:       "rs256",
+			token:      sign(t, RS256, "rsa", rsaKey, claims(nil)),
+			scopes:     []string{"tasks.read"},
+			wantScopes: []string{"tasks.read", "tasks.write"},
+		},
+		{
+			name:       "es256 with scp array",
+			token:      sign(t, ES256, "ec", ecKey, claims(map[string]any{"scope": nil, "scp": []string{"tasks.read"}})),
+			scopes:     []string{"tasks.read"},
+			wantScopes: []string{"tasks.read"},
+		},
+		{
+			name:       "audience as string",
+			token:      sign(t, RS256, "rsa", rsaKey, claims(map[string]any{"aud": "https://agent.example.com"})),
+			wantScopes: []string{"tasks.read", "tasks.write"},
+		},
+		{
+			name:    "expired",
+			token:   sign(t, RS256, "rsa", rsaKey, claims(map[string]any{"exp": now.Add(-time.Minute).Unix()})),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "without exp",
+			token:   sign(t, RS256, "rsa", rsaKey, claims(map[string]any{"exp": nil})),
+			wantErr: ErrMissingExpiration,
+		},
+		{
+			name:    "not valid yet",
+			token:   sign(t, RS256, "rsa", rsaKey, claims(map[string]any{"nbf": now.Add(time.Minute).Unix()})),
+			wantErr: ErrTokenNotValidYet,
+		},
+		{
+			name:    "wrong issuer",
+			token:   sign(t, RS256, "rsa", rsaKey, claims(map[string]any{"iss": "https://evil.example.com"})),
+			wantErr: ErrInvalidIssuer,
+		},
+		{
+			name:    "wrong audience",
+			token:   sign(t, RS256, "rsa", rsaKey, claims(map[string]any{"aud": "https://other.example.com"})),
+			wantErr: ErrInvalidAudience,
+		},
+		{
+			name:    "missing scope",
+			token:   sign(t, RS256, "rsa", rsaKey, claims(nil)),
+			scopes:  []string{"tasks.admin"},
+			wantErr: ErrInsufficientScope,
+		},
+		{
+			name:    "signed by another key",
+			token:   sign(t, RS256, "rsa", otherKey, claims(nil)),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "unknown kid",
+			token:   sign(t, RS256, "unknown", otherKey, claims(nil)),
+			wantErr: ErrUnknownKey,
+		},
+		{
+			name:    "algorithm not allowed",
+			token:   sign(t, HS256, "rsa", []byte("secret"), claims(nil)),
+			wantErr: ErrUnsupportedAlg,
+		},
+		{
+			name:    "malformed",
+			token:   "not.a-token",
+			wantErr: ErrMalformedToken,
+		},
+	}
+
+	verifier := NewVerifier(
+		NewJWKS(server.URL),
+		WithIssuer("https://issuer.example.com"),
+		WithAudience("https://agent.example.com"),
+	)
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, err := verifier.Verify(context.Background(), tc.token, tc.scopes)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, user.IsAuthenticated())
+			assert.Equal(t, "alice", user.UserName())
+			assert.Equal(t, tc.wantScopes, user.Scopes())
+			assert.Equal(t, "https://issuer.example.com", user.Claims().String("iss"))
+		})
+	}
+}
+
+func TestVerifierHS256(t *testing.T) {
+	secret := []byte("shared-secret")
+	verifier := NewVerifier(HMACSecret(secret), WithAlgorithms(HS256), WithOptionalExpiration())
+
+	token := sign(t, HS256, "", secret, map[string]any{"sub": "bob"})
+	user, err := verifier.Validate(context.Background(), token, nil)
+	require.NoError(t, err, "token without exp accepted by the option")
+	assert.Equal(t, "bob", user.UserName())
+
+	token = sign(t, HS256, "", []byte("other-secret"), map[string]any{"sub": "bob"})
+	_, err = verifier.Validate(context.Background(), token, nil)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestJWKSRotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	server := newJWKSServer(rsaJWK("old", oldKey))
+	defer server.Close()
+
+	now := time.Now()
+	jwks := NewJWKS(server.URL, WithJWKSTTL(time.Hour), WithMinRefreshPeriod(time.Minute))
+	jwks.now = func() time.Time { return now }
+	verifier := NewVerifier(jwks)
+
+	_, err = verifier.Verify(context.Background(), sign(t, RS256, "old", oldKey, map[string]any{"sub": "alice", "exp": now.Add(3 * time.Hour).Unix()}), nil)
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), sign(t, RS256, "old", oldKey, map[string]any{"sub": "alice", "exp": now.Add(3 * time.Hour).Unix()}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), server.fetches.Load(), "key set is cached")
+
+	server.setKeys(rsaJWK("new", newKey))
+	rotated := sign(t, RS256, "new", newKey, map[string]any{"sub": "alice", "exp": now.Add(3 * time.Hour).Unix()})
+	_, err = verifier.Verify(context.Background(), rotated, nil)
+	require.ErrorIs(t, err, ErrUnknownKey, "unknown kid is not refetched within the min refresh period")
+	assert.Equal(t, int32(1), server.fetches.Load())
+
+	now = now.Add(2 * time.Minute)
+	_, err = verifier.Verify(context.Background(), rotated, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), server.fetches.Load())
+
+	now = now.Add(2 * time.Hour)
+	_, err = verifier.Verify(context.Background(), rotated, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), server.fetches.Load(), "key set is fetched again after the ttl")
+}
+
+func TestJWKSConcurrentRefresh(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys := []map[string]string{rsaJWK("old", oldKey)}
+		if fetches.Add(1) > 1 {
+			<-release
+			keys = append(keys, rsaJWK("new", newKey))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	defer server.Close()
+
+	jwks := NewJWKS(server.URL, WithMinRefreshPeriod(0))
+	_, err = jwks.Key(context.Background(), "old", RS256)
+	require.NoError(t, err)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	refreshed := make(chan error, 1)
+	go func() {
+		_, err := jwks.Key(canceled, "new", RS256)
+		refreshed <- err
+	}()
+	require.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	_, err = jwks.Key(context.Background(), "old", RS256)
+	require.NoError(t, err, "cached keys are usable while the key set is fetched")
+
+	waiting := make(chan error, 1)
+	go func() {
+		_, err := jwks.Key(context.Background(), "new", RS256)
+		waiting <- err
+	}()
+	cancel()
+	require.ErrorIs(t, <-refreshed, context.Canceled)
+
+	close(release)
+	require.NoError(t, <-waiting, "the fetch outlives the canceled verification")
+	assert.Equal(t, int32(2), fetches.Load(), "concurrent lookups share the fetch")
+}
+
+func TestJWKSFailedFetchBackoff(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	var available atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{rsaJWK("rsa", key)}})
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	jwks := NewJWKS(server.URL, WithMinRefreshPeriod(time.Minute))
+	jwks.now = func() time.Time { return now }
+
+	for range 3 {
+		_, err = jwks.Key(context.Background(), "rsa", RS256)
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(1), fetches.Load(), "a failed fetch is not retried within the min refresh period")
+
+	available.Store(true)
+	now = now.Add(2 * time.Minute)
+	_, err = jwks.Key(context.Background(), "rsa", RS256)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	available.Store(false)
+	now = now.Add(2 * time.Minute)
+	for range 3 {
+		_, err = jwks.Key(context.Background(), "unknown", RS256)
+		require.ErrorIs(t, err, ErrUnknownKey)
+	}
+	assert.Equal(t, int32(3), fetches.Load(), "unknown kids do not call an unavailable issuer again")
+	_, err = jwks.Key(context.Background(), "rsa", RS256)
+	require.NoError(t, err, "the cached keys remain usable")
+}
@@ -23,3 +23,27 @@ type UnauthenticatedUser interface {
 	IsAuthenticated() bool
 	UserName() string
 }
+
+// BasicUser is an authenticated User identified by its name.
+type BasicUser struct {
+	Name string
+}
+
+func (u BasicUser) IsAuthenticated() bool {
+	return true
+}
+
+func (u BasicUser) UserName() string {
+	return u.Name
+}
+
+// AnonymousUser is the User of a request that is not authenticated.
+type AnonymousUser struct{}
+
+func (u AnonymousUser) IsAuthenticated() bool {
+	return false
+}
+
+func (u AnonymousUser) UserName() string {
+	return ""
+}
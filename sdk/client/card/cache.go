@@ -0,0 +1,271 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package card
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+const (
+	defaultCardTTL              = 5 * time.Minute
+	defaultStaleWhileRevalidate = time.Minute
+	defaultNegativeTTL          = 10 * time.Second
+	defaultFetchTimeout         = 30 * time.Second
+)
+
+// CachingResolver retrieves the agent cards of many agents and caches them by URL.
+// A cached card is served for the TTL, then revalidated with If-None-Match. During the
+// stale-while-revalidate window after the TTL, the stale card is served while it is
+// revalidated in the background. Failures are cached for the negative TTL.
+// Concurrent lookups of the same card share a single HTTP request.
+// The cards are fetched by an A2ACardResolver configured by WithResolverOptions.
+// It is safe for concurrent use.
+type CachingResolver struct {
+	client               *http.Client
+	agentCardPath        string
+	headers              map[string]string
+	resolverOptions      []A2ACardResolverOption
+	resolver             *A2ACardResolver // Fetches the cards, once its base url is set
+	ttl                  time.Duration
+	staleWhileRevalidate time.Duration
+	negativeTTL          time.Duration
+	fetchTimeout         time.Duration
+	now                  func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry // card url -> entry
+	inflight map[string]*fetchCall  // card url -> fetch in progress
+}
+
+type cacheEntry struct {
+	card    *types.AgentCard // nil for a cached failure
+	etag    string
+	err     error
+	expires time.Time
+	retryAt time.Time // when a stale card whose revalidation failed is revalidated again
+}
+
+type fetchCall struct {
+	done  chan struct{}
+	entry *cacheEntry
+}
+
+// NewCachingResolver creates a CachingResolver using the given HTTP client.
+func NewCachingResolver(client *http.Client, opts ...CachingResolverOption) *CachingResolver {
+	c := &CachingResolver{
+		client:               client,
+		agentCardPath:        types.AgentCardPath,
+		ttl:                  defaultCardTTL,
+		staleWhileRevalidate: defaultStaleWhileRevalidate,
+		negativeTTL:          defaultNegativeTTL,
+		fetchTimeout:         defaultFetchTimeout,
+		now:                  time.Now,
+		entries:              make(map[string]*cacheEntry),
+		inflight:             make(map[string]*fetchCall),
+	}
+	for _, opt := range opts {
+		opt.Option(c)
+	}
+	options := append(slices.Clone(c.resolverOptions), WithAgentCardPath(c.agentCardPath), WithHeader(c.headers))
+	c.resolver = NewA2ACardResolver(c.client, "", options...)
+	return c
+}
+
+// GetAgentCard returns the agent card of the agent at baseURL, from the cache when possible.
+// The returned card is a copy that the caller may modify.
+func (c *CachingResolver) GetAgentCard(ctx context.Context, baseURL string) (*types.AgentCard, error) {
+	targetURL, err := url.JoinPath(baseURL, c.agentCardPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct url %w", err)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[targetURL]
+	now := c.now()
+	switch {
+	case ok && now.Before(entry.expires):
+		c.mu.Unlock()
+		return entry.result()
+	case ok && entry.card != nil && now.Before(entry.expires.Add(c.staleWhileRevalidate)):
+		if !now.Before(entry.retryAt) {
+			c.startFetch(baseURL, targetURL, entry)
+		}
+		c.mu.Unlock()
+		return entry.result()
+	}
+	call := c.startFetch(baseURL, targetURL, entry)
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.entry.result()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Invalidate drops the cached card of the agent at baseURL.
+func (c *CachingResolver) Invalidate(baseURL string) {
+	targetURL, err := url.JoinPath(baseURL, c.agentCardPath)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, targetURL)
+}
+
+// startFetch starts fetching the card unless a fetch is already in progress, and returns
+// the call to wait for. The caller must hold the lock. The fetch is not bound to the context
+// of the caller, so that a canceled lookup does not fail the lookups sharing the fetch.
+func (c *CachingResolver) startFetch(baseURL, targetURL string, previous *cacheEntry) *fetchCall {
+	if call, ok := c.inflight[targetURL]; ok {
+		return call
+	}
+	call := &fetchCall{done: make(chan struct{})}
+	c.inflight[targetURL] = call
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), c.fetchTimeout)
+		entry := c.fetch(ctx, baseURL, previous)
+		cancel()
+
+		c.mu.Lock()
+		if entry.err != nil && previous != nil && previous.card != nil && c.now().Before(previous.expires.Add(c.staleWhileRevalidate)) {
+			log.Warnf("failed to revalidate agent card %s, serving the stale card: %v", targetURL, entry.err)
+			stale := *previous
+			stale.retryAt = c.now().Add(c.negativeTTL)
+			entry = &stale
+		}
+		c.entries[targetURL] = entry
+		delete(c.inflight, targetURL)
+		c.mu.Unlock()
+
+		call.entry = entry
+		close(call.done)
+	}()
+	return call
+}
+
+// fetch retrieves the card, revalidating the previous one when it has an entity tag.
+func (c *CachingResolver) fetch(ctx context.Context, baseURL string, previous *cacheEntry) *cacheEntry {
+	var etag string
+	if previous != nil && previous.card != nil {
+		etag = previous.etag
+	}
+	resolver := *c.resolver
+	resolver.baseUrl = baseURL
+	card, etag, err := resolver.revalidateAgentCard(ctx, etag)
+	if err != nil {
+		return c.failure(err)
+	}
+	if card == nil {
+		// Not modified
+		card = previous.card
+	}
+	return &cacheEntry{card: card, etag: etag, expires: c.now().Add(c.ttl)}
+}
+
+func (c *CachingResolver) failure(err error) *cacheEntry {
+	return &cacheEntry{err: err, expires: c.now().Add(c.negativeTTL)}
+}
+
+// result returns a deep copy of the cached card, so that callers do not share its slices and maps.
+func (e *cacheEntry) result() (*types.AgentCard, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	data, err := json.Marshal(e.card)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy agent card: %w", err)
+	}
+	var card types.AgentCard
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("failed to copy agent card: %w", err)
+	}
+	return &card, nil
+}
+
+// CachingResolverOption allows customizing CachingResolver via functional options.
+type CachingResolverOption interface {
+	Option(c *CachingResolver)
+}
+
+// CachingResolverOptionFunc is a function type for CachingResolverOption.
+type CachingResolverOptionFunc func(c *CachingResolver)
+
+func (fn CachingResolverOptionFunc) Option(c *CachingResolver) {
+	fn(c)
+}
+
+// WithTTL sets how long a fetched card is served without revalidation.
+func WithTTL(ttl time.Duration) CachingResolverOption {
+	return CachingResolverOptionFunc(func(c *CachingResolver) {
+		c.ttl = ttl
+	})
+}
+
+// WithStaleWhileRevalidate sets how long after its TTL a card is still served while it is revalidated.
+func WithStaleWhileRevalidate(window time.Duration) CachingResolverOption {
+	return CachingResolverOptionFunc(func(c *CachingResolver) {
+		c.staleWhileRevalidate = window
+	})
+}
+
+// WithNegativeTTL sets how long a failure to fetch a card is cached.
+func WithNegativeTTL(ttl time.Duration) CachingResolverOption {
+	return CachingResolverOptionFunc(func(c *CachingResolver) {
+		c.negativeTTL = ttl
+	})
+}
+
+// WithFetchTimeout sets the timeout of a card request, which is not bound to the context of a lookup.
+func WithFetchTimeout(timeout time.Duration) CachingResolverOption {
+	return CachingResolverOptionFunc(func(c *CachingResolver) {
+		c.fetchTimeout = timeout
+	})
+}
+
+// WithCachedCardPath sets the path of the agent card endpoint, types.AgentCardPath by default.
+func WithCachedCardPath(path string) CachingResolverOption {
+	return CachingResolverOptionFunc(func(c *CachingResolver) {
+		c.agentCardPath = path
+	})
+}
+
+// WithResolverOptions configures the A2ACardResolver fetching the cards, for instance
+// WithValidation to refuse invalid cards or WithClientCertificate for agents requiring mutual TLS.
+func WithResolverOptions(opts ...A2ACardResolverOption) CachingResolverOption {
+	return CachingResolverOptionFunc(func(c *CachingResolver) {
+		c.resolverOptions = append(c.resolverOptions, opts...)
+	})
+}
+
+// WithCacheHeaders sets HTTP headers included in the agent card requests.
+func WithCacheHeaders(headers map[string]string) CachingResolverOption {
+	return CachingResolverOptionFunc(func(c *CachingResolver) {
+		c.headers = headers
+	})
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package card
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+type cardServer struct {
+	*httptest.Server
+	fetches     atomic.Int32
+	revalidated atomic.Int32
+	status      atomic.Int32
+	version     atomic.Value
+	release     chan struct{}
+}
+
+func newCardServer(t *testing.T) *cardServer {
+	s := &cardServer{}
+	s.status.Store(http.StatusOK)
+	s.version.Store("1.0.0")
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		if s.release != nil {
+			<-s.release
+		}
+		if status := int(s.status.Load()); status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		version := s.version.Load().(string)
+		etag := `"` + version + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			s.revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(types.AgentCard{Name: "agent", Version: version})
+		assert.NoError(t, err)
+	}))
+	return s
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestCachingResolver(clock *fakeClock) *CachingResolver {
+	resolver := NewCachingResolver(
+		http.DefaultClient,
+		WithTTL(time.Minute),
+		WithStaleWhileRevalidate(30*time.Second),
+		WithNegativeTTL(10*time.Second),
+	)
+	resolver.now = clock.Now
+	return resolver
+}
+
+func TestCachingResolver(t *testing.T) {
+	server := newCardServer(t)
+	defer server.Close()
+	clock := &fakeClock{now: time.Now()}
+	resolver := newTestCachingResolver(clock)
+
+	card, err := resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", card.Version)
+
+	card.Version = "modified"
+	card, err = resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", card.Version, "the cached card is not shared with callers")
+	assert.Equal(t, int32(1), server.fetches.Load())
+
+	clock.Advance(2 * time.Minute)
+	card, err = resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", card.Version)
+	assert.Equal(t, int32(2), server.fetches.Load())
+	assert.Equal(t, int32(1), server.revalidated.Load(), "expired card is revalidated with its etag")
+
+	server.version.Store("2.0.0")
+	resolver.Invalidate(server.URL)
+	card, err = resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", card.Version)
+}
+
+func TestCachingResolverStaleWhileRevalidate(t *testing.T) {
+	server := newCardServer(t)
+	defer server.Close()
+	clock := &fakeClock{now: time.Now()}
+	resolver := newTestCachingResolver(clock)
+
+	_, err := resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+
+	server.version.Store("2.0.0")
+	clock.Advance(time.Minute + 10*time.Second)
+	card, err := resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", card.Version, "stale card is served while it is revalidated")
+
+	require.Eventually(t, func() bool {
+		card, err := resolver.GetAgentCard(t.Context(), server.URL)
+		return err == nil && card.Version == "2.0.0"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), server.fetches.Load())
+}
+
+func TestCachingResolverStaleOnError(t *testing.T) {
+	server := newCardServer(t)
+	defer server.Close()
+	clock := &fakeClock{now: time.Now()}
+	resolver := newTestCachingResolver(clock)
+
+	_, err := resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+
+	server.status.Store(http.StatusServiceUnavailable)
+	clock.Advance(time.Minute + time.Second)
+	card, err := resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", card.Version)
+	require.Eventually(t, func() bool {
+		resolver.mu.Lock()
+		defer resolver.mu.Unlock()
+		return len(resolver.inflight) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	for range 3 {
+		card, err = resolver.GetAgentCard(t.Context(), server.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", card.Version)
+	}
+	assert.Equal(t, int32(2), server.fetches.Load(), "failed revalidation is not retried before the negative ttl")
+
+	clock.Advance(30 * time.Second)
+	_, err = resolver.GetAgentCard(t.Context(), server.URL)
+	assert.Error(t, err, "stale card is not served after the stale-while-revalidate window")
+}
+
+func TestCachingResolverNegativeCaching(t *testing.T) {
+	server := newCardServer(t)
+	defer server.Close()
+	server.status.Store(http.StatusNotFound)
+	clock := &fakeClock{now: time.Now()}
+	resolver := newTestCachingResolver(clock)
+
+	_, err := resolver.GetAgentCard(t.Context(), server.URL)
+	require.Error(t, err)
+	_, err = resolver.GetAgentCard(t.Context(), server.URL)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), server.fetches.Load())
+
+	server.status.Store(http.StatusOK)
+	clock.Advance(11 * time.Second)
+	card, err := resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", card.Version)
+	assert.Equal(t, int32(2), server.fetches.Load())
+}
+
+func TestCachingResolverSingleFlight(t *testing.T) {
+	server := newCardServer(t)
+	server.release = make(chan struct{})
+	defer server.Close()
+	clock := &fakeClock{now: time.Now()}
+	resolver := newTestCachingResolver(clock)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			card, err := resolver.GetAgentCard(t.Context(), server.URL)
+			assert.NoError(t, err)
+			assert.Equal(t, "1.0.0", card.Version)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return server.fetches.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+	close(server.release)
+	wg.Wait()
+	assert.Equal(t, int32(1), server.fetches.Load())
+}
+
+func TestCachingResolverCopiesCards(t *testing.T) {
+	served := types.AgentCard{
+		Name:     "agent",
+		Version:  "1.0.0",
+		URL:      "https://agent.example.com",
+		Skills:   []types.AgentSkill{{ID: "search", Name: "Search", Tags: []string{"web"}}},
+		Security: types.SecurityRequirement{{"apiKey": nil}},
+		SecuritySchemes: map[string]types.SecurityScheme{
+			"apiKey": types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-API-Key"},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(served))
+	}))
+	defer server.Close()
+	resolver := NewCachingResolver(http.DefaultClient)
+
+	card, err := resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	card.Skills[0].Tags[0] = "modified"
+	card.Skills = append(card.Skills, types.AgentSkill{ID: "other"})
+	card.SecuritySchemes["bearer"] = types.HTTPAuthSecurityScheme{Type: types.HTTP, Scheme: "bearer"}
+	delete(card.SecuritySchemes, "apiKey")
+
+	card, err = resolver.GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, served.Skills, card.Skills, "the slices of the cached card are not shared")
+	assert.Equal(t, served.SecuritySchemes, card.SecuritySchemes, "the maps of the cached card are not shared")
+}
+
+func TestCachingResolverValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(types.AgentCard{Version: "1.0.0"}))
+	}))
+	defer server.Close()
+
+	_, err := NewCachingResolver(http.DefaultClient).GetAgentCard(t.Context(), server.URL)
+	require.NoError(t, err)
+
+	resolver := NewCachingResolver(http.DefaultClient, WithResolverOptions(WithValidation()))
+	_, err = resolver.GetAgentCard(t.Context(), server.URL)
+	var validationErr *types.ValidationError
+	require.ErrorAs(t, err, &validationErr, "the card is validated by the resolver options")
+}
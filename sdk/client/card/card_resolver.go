@@ -16,13 +16,20 @@ package card
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 
 	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/client"
+	"github.com/yeeaiclub/a2a-go/sdk/client/middleware"
 	"github.com/yeeaiclub/a2a-go/sdk/types"
+	"github.com/yeeaiclub/a2a-go/sdk/web"
 )
 
 // A2ACardResolver is responsible for retrieving agent card information from a specified endpoint.
@@ -32,6 +39,11 @@ type A2ACardResolver struct {
 	baseUrl       string            // Base URL of the agent service
 	agentCardPath string            // Path to the agent card endpoint
 	options       map[string]string // HTTP headers to include in requests
+
+	extendedCardPath string               // Path to the authenticated extended agent card endpoint
+	middlewares      []web.MiddlewareFunc // Middlewares authenticating extended agent card requests
+	validate         bool                 // Whether retrieved cards are validated
+	err              error                // Configuration error, returned by every request
 }
 
 // NewA2ACardResolver creates a new instance of A2ACardResolver with the provided configuration.
@@ -40,6 +52,8 @@ func NewA2ACardResolver(client *http.Client, baseURL string, options ...A2ACardR
 		client:        client,
 		baseUrl:       baseURL,
 		agentCardPath: types.AgentCardPath, // Default path from types package
+
+		extendedCardPath: types.ExtendedAgentCardPath,
 	}
 
 	// Apply all provided configuration options
@@ -52,14 +66,82 @@ func NewA2ACardResolver(client *http.Client, baseURL string, options ...A2ACardR
 // GetAgentCard retrieves an agent card from the configured base URL.
 // It sends an HTTP GET request to the agent card endpoint and returns the parsed card data.
 func (a *A2ACardResolver) GetAgentCard(ctx context.Context) (*types.AgentCard, error) {
-	targetURL, err := url.JoinPath(a.baseUrl, a.agentCardPath)
+	card, _, err := a.revalidateAgentCard(ctx, "")
+	return card, err
+}
+
+// revalidateAgentCard retrieves the agent card with its entity tag. When etag is not empty
+// and still matches the card, a nil card is returned: the card of the caller is up to date.
+func (a *A2ACardResolver) revalidateAgentCard(ctx context.Context, etag string) (*types.AgentCard, string, error) {
+	card, etag, err := a.fetchIfNoneMatch(ctx, a.agentCardPath, nil, etag)
+	if err != nil || card == nil {
+		return nil, etag, err
+	}
+	if err := a.validateCard(card); err != nil {
+		return nil, "", err
+	}
+	return card, etag, nil
+}
+
+// GetExtendedAgentCard retrieves the authenticated extended agent card and merges it over the public card.
+// The request is authenticated by the middlewares of the resolver, according to the security requirement
+// of the public card. If public is nil, the public card is retrieved first. When the public card does not
+// support an extended card, it is returned as is.
+func (a *A2ACardResolver) GetExtendedAgentCard(ctx context.Context, public *types.AgentCard) (*types.AgentCard, error) {
+	if public == nil {
+		var err error
+		public, err = a.GetAgentCard(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !public.SupportsAuthenticatedExtendedCard {
+		return public, nil
+	}
+
+	extended, err := a.fetch(ctx, a.extendedCardPath, public)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct url %w", err)
+		return nil, fmt.Errorf("failed to get extended agent card: %w", err)
+	}
+	merged := MergeAgentCard(public, extended)
+	if err := a.validateCard(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// validateCard returns a *types.ValidationError if validation is enabled and the card has errors.
+func (a *A2ACardResolver) validateCard(card *types.AgentCard) error {
+	if !a.validate {
+		return nil
+	}
+	return card.Validate().Err()
+}
+
+// fetch retrieves the card at the path. When public is not nil, the request is passed through
+// the middlewares with the security configuration of the public card.
+func (a *A2ACardResolver) fetch(ctx context.Context, path string, public *types.AgentCard) (*types.AgentCard, error) {
+	card, _, err := a.fetchIfNoneMatch(ctx, path, public, "")
+	return card, err
+}
+
+// fetchIfNoneMatch retrieves the card at the path like fetch, with its entity tag. When etag
+// is not empty, the request is conditional, and a nil card is returned if it is not modified.
+func (a *A2ACardResolver) fetchIfNoneMatch(ctx context.Context, path string, public *types.AgentCard, etag string) (*types.AgentCard, string, error) {
+	if a.err != nil {
+		return nil, "", a.err
+	}
+	targetURL, err := url.JoinPath(a.baseUrl, path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to construct url %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to construct request %w", err)
+		return nil, "", fmt.Errorf("failed to construct request %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
 	}
 
 	if a.options != nil {
@@ -68,9 +150,15 @@ func (a *A2ACardResolver) GetAgentCard(ctx context.Context) (*types.AgentCard, e
 		}
 	}
 
+	if public != nil {
+		if err := a.authenticate(req, public); err != nil {
+			return nil, "", err
+		}
+	}
+
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	defer func() {
@@ -79,16 +167,93 @@ func (a *A2ACardResolver) GetAgentCard(ctx context.Context) (*types.AgentCard, e
 		}
 	}()
 
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, etag, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get agent card: status code %d", resp.StatusCode)
+		return nil, "", fmt.Errorf("failed to get agent card: status code %d", resp.StatusCode)
 	}
 
 	var card types.AgentCard
 	err = json.NewDecoder(resp.Body).Decode(&card)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse body: %w", err)
+		return nil, "", fmt.Errorf("failed to parse body: %w", err)
+	}
+	return &card, resp.Header.Get("ETag"), nil
+}
+
+// authenticate runs the middlewares over the request with the security configuration of the card.
+func (a *A2ACardResolver) authenticate(req *http.Request, card *types.AgentCard) error {
+	ctx := middleware.NewCallContext(0)
+	ctx.SetRequest(req)
+	ctx.SetSecurityConfig(card.Security, card.SecuritySchemes)
+
+	handler := func(ctx web.Context) error {
+		return nil
+	}
+	for i := len(a.middlewares) - 1; i >= 0; i-- {
+		handler = a.middlewares[i](handler)
+	}
+	return handler(ctx)
+}
+
+// MergeAgentCard returns the public card overridden by the fields the extended card sets.
+// Skills are merged by id, the extended skill replacing the public skill with the same id,
+// and security schemes are merged by name.
+func MergeAgentCard(public, extended *types.AgentCard) *types.AgentCard {
+	merged := *public
+	if extended == nil {
+		return &merged
+	}
+	if extended.Name != "" {
+		merged.Name = extended.Name
+	}
+	if extended.Description != "" {
+		merged.Description = extended.Description
+	}
+	if extended.URL != "" {
+		merged.URL = extended.URL
+	}
+	if extended.Version != "" {
+		merged.Version = extended.Version
+	}
+	if extended.IconUrl != "" {
+		merged.IconUrl = extended.IconUrl
+	}
+	if len(extended.DefaultInputModes) > 0 {
+		merged.DefaultInputModes = extended.DefaultInputModes
+	}
+	if len(extended.DefaultOutputModes) > 0 {
+		merged.DefaultOutputModes = extended.DefaultOutputModes
+	}
+	if extended.Provider != nil {
+		merged.Provider = extended.Provider
+	}
+	if extended.Capabilities != nil {
+		merged.Capabilities = extended.Capabilities
 	}
-	return &card, nil
+	if len(extended.Security) > 0 {
+		merged.Security = extended.Security
+	}
+
+	merged.Skills = slices.Clone(public.Skills)
+	for _, skill := range extended.Skills {
+		i := slices.IndexFunc(merged.Skills, func(s types.AgentSkill) bool {
+			return s.ID != "" && s.ID == skill.ID
+		})
+		if i >= 0 {
+			merged.Skills[i] = skill
+			continue
+		}
+		merged.Skills = append(merged.Skills, skill)
+	}
+
+	if len(extended.SecuritySchemes) > 0 {
+		merged.SecuritySchemes = make(map[string]types.SecurityScheme, len(public.SecuritySchemes)+len(extended.SecuritySchemes))
+		maps.Copy(merged.SecuritySchemes, public.SecuritySchemes)
+		maps.Copy(merged.SecuritySchemes, extended.SecuritySchemes)
+	}
+	return &merged
 }
 
 // A2ACardResolverOption defines an interface for configuring A2ACardResolver instances.
@@ -119,3 +284,48 @@ func WithHeader(header map[string]string) A2ACardResolverOption {
 		return resolver
 	})
 }
+
+// WithMiddlewares configures the middlewares authenticating extended agent card requests,
+// such as middleware.Intercept with the credentials of the caller.
+func WithMiddlewares(middlewares ...web.MiddlewareFunc) A2ACardResolverOption {
+	return A2ACardResolverOptionFunc(func(resolver A2ACardResolver) A2ACardResolver {
+		resolver.middlewares = append(resolver.middlewares, middlewares...)
+		return resolver
+	})
+}
+
+// WithExtendedAgentCardPath configures the path used to retrieve the authenticated extended agent card.
+// By default, the resolver uses the path defined in types.ExtendedAgentCardPath.
+func WithExtendedAgentCardPath(path string) A2ACardResolverOption {
+	return A2ACardResolverOptionFunc(func(resolver A2ACardResolver) A2ACardResolver {
+		resolver.extendedCardPath = path
+		return resolver
+	})
+}
+
+// WithValidation configures the resolver to validate the retrieved cards, returning a
+// *types.ValidationError for a card with errors. The extended card is validated once
+// merged over the public card.
+func WithValidation() A2ACardResolverOption {
+	return A2ACardResolverOptionFunc(func(resolver A2ACardResolver) A2ACardResolver {
+		resolver.validate = true
+		return resolver
+	})
+}
+
+// WithClientCertificate configures the resolver to present the certificate to agents requiring
+// mutual TLS, which also protects the agent card of an agent started with handler.WithMutualTLS.
+// The authorities verify the server certificate, the system ones being used if rootCAs is nil.
+// If the certificate cannot be set on the HTTP client, see client.NewMutualTLSHTTPClient,
+// every request returns the error.
+func WithClientCertificate(cert tls.Certificate, rootCAs *x509.CertPool) A2ACardResolverOption {
+	return A2ACardResolverOptionFunc(func(resolver A2ACardResolver) A2ACardResolver {
+		mutualTLSClient, err := client.NewMutualTLSHTTPClient(resolver.client, cert, rootCAs)
+		if err != nil {
+			resolver.err = err
+			return resolver
+		}
+		resolver.client = mutualTLSClient
+		return resolver
+	})
+}
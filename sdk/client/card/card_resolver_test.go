@@ -22,7 +22,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/client/middleware"
 	"github.com/yeeaiclub/a2a-go/sdk/types"
+	"github.com/yeeaiclub/a2a-go/sdk/web"
 )
 
 func TestGetAgentCard(t *testing.T) {
@@ -63,3 +65,121 @@ func TestGetAgentCard(t *testing.T) {
 		})
 	}
 }
+
+type staticCredential string
+
+func (c staticCredential) GetCredentials(securitySchemeName string, context web.Context) (string, error) {
+	return string(c), nil
+}
+
+func TestGetExtendedAgentCard(t *testing.T) {
+	public := &types.AgentCard{
+		Name:    "agent",
+		Version: "1.0.0",
+		Skills: []types.AgentSkill{
+			{ID: "search", Name: "search"},
+		},
+		Security: types.SecurityRequirement{{"bearer": []string{}}},
+		SecuritySchemes: map[string]types.SecurityScheme{
+			"bearer": types.HTTPAuthSecurityScheme{Type: types.HTTP, Scheme: "bearer"},
+		},
+		SupportsAuthenticatedExtendedCard: true,
+	}
+	extended := &types.AgentCard{
+		Name:        "agent",
+		Description: "the agent with its internal skills",
+		Skills: []types.AgentSkill{
+			{ID: "search", Name: "search", Description: "search internal documents"},
+			{ID: "admin", Name: "admin"},
+		},
+	}
+
+	testcases := []struct {
+		name       string
+		public     *types.AgentCard
+		credential string
+		want       *types.AgentCard
+		wantErr    bool
+	}{
+		{
+			name:       "merged over the public card",
+			public:     public,
+			credential: "valid",
+			want: &types.AgentCard{
+				Name:        "agent",
+				Description: "the agent with its internal skills",
+				Version:     "1.0.0",
+				Skills: []types.AgentSkill{
+					{ID: "search", Name: "search", Description: "search internal documents"},
+					{ID: "admin", Name: "admin"},
+				},
+				Security:                          public.Security,
+				SecuritySchemes:                   public.SecuritySchemes,
+				SupportsAuthenticatedExtendedCard: true,
+			},
+		},
+		{
+			name:       "rejected credentials",
+			public:     public,
+			credential: "invalid",
+			wantErr:    true,
+		},
+		{
+			name:   "extended card not supported",
+			public: &types.AgentCard{Name: "agent", Version: "1.0.0"},
+			want:   &types.AgentCard{Name: "agent", Version: "1.0.0"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, types.ExtendedAgentCardPath, r.URL.Path)
+				if r.Header.Get("Authorization") != "Bearer valid" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				err := json.NewEncoder(w).Encode(extended)
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			resolver := NewA2ACardResolver(
+				http.DefaultClient,
+				server.URL,
+				WithMiddlewares(middleware.Intercept(staticCredential(tc.credential))),
+			)
+			card, err := resolver.GetExtendedAgentCard(t.Context(), tc.public)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, card)
+		})
+	}
+}
+
+func TestGetAgentCardValidation(t *testing.T) {
+	invalid := &types.AgentCard{
+		Name:    "agent",
+		URL:     "https://agent.example.com",
+		Version: "1.0.0",
+		Skills:  []types.AgentSkill{{ID: "search", Name: "search"}, {ID: "search", Name: "search"}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		err := json.NewEncoder(w).Encode(invalid)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	card, err := NewA2ACardResolver(http.DefaultClient, server.URL).GetAgentCard(t.Context())
+	require.NoError(t, err, "cards are not validated by default")
+	assert.Equal(t, invalid, card)
+
+	_, err = NewA2ACardResolver(http.DefaultClient, server.URL, WithValidation()).GetAgentCard(t.Context())
+	var validationErr *types.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "skills[1].id", validationErr.Diagnostics[0].Field)
+}
@@ -23,7 +23,6 @@ import (
 	"io"
 	"net/http"
 
-	"github.com/google/uuid"
 	"github.com/yeeaiclub/a2a-go/internal/jsonx"
 	log "github.com/yeeaiclub/a2a-go/internal/logger"
 	"github.com/yeeaiclub/a2a-go/sdk/client/middleware"
@@ -36,6 +35,13 @@ type A2AClient struct {
 	clint       *http.Client
 	url         string
 	middlewares []web.MiddlewareFunc
+	retryPolicy *RetryPolicy
+
+	idGenerator   IDGenerator
+	strictIDCheck bool
+
+	certificate *clientCertificate
+	err         error // Configuration error, returned by every call
 }
 
 type A2AClientOption interface {
@@ -56,23 +62,37 @@ func WithAgentCard(card *types.AgentCard) A2AClientOption {
 
 func NewClient(client *http.Client, url string, options ...A2AClientOption) *A2AClient {
 	a2aClient := &A2AClient{
-		clint: client,
-		url:   url,
+		clint:       client,
+		url:         url,
+		idGenerator: UUIDGenerator(),
 	}
 	for _, opt := range options {
 		opt.Option(a2aClient)
 	}
+	if c := a2aClient.certificate; c != nil && (a2aClient.card == nil || a2aClient.card.RequiresMutualTLS()) {
+		mutualTLSClient, err := NewMutualTLSHTTPClient(a2aClient.clint, c.cert, c.rootCAs)
+		if err != nil {
+			a2aClient.err = err
+		} else {
+			a2aClient.clint = mutualTLSClient
+		}
+	}
 	return a2aClient
 }
 
 func (c *A2AClient) SendMessage(params types.MessageSendParam) (*types.JSONRPCResponse, error) {
+	return c.SendMessageContext(context.Background(), params)
+}
+
+// SendMessageContext is like SendMessage, the call and its retries are canceled with the context.
+func (c *A2AClient) SendMessageContext(ctx context.Context, params types.MessageSendParam) (*types.JSONRPCResponse, error) {
 	req := types.SendMessageRequest{
-		Id:     uuid.New().String(),
+		Id:     c.idGenerator.Generate(),
 		Method: types.MethodMessageSend,
 		Params: params,
 	}
 	var resp types.JSONRPCResponse
-	err := c.sendRequest(req, &resp)
+	err := c.sendRequest(ctx, req.Id, req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -80,14 +100,19 @@ func (c *A2AClient) SendMessage(params types.MessageSendParam) (*types.JSONRPCRe
 }
 
 func (c *A2AClient) GetTask(params types.TaskQueryParams) (*types.JSONRPCResponse, error) {
+	return c.GetTaskContext(context.Background(), params)
+}
+
+// GetTaskContext is like GetTask, the call and its retries are canceled with the context.
+func (c *A2AClient) GetTaskContext(ctx context.Context, params types.TaskQueryParams) (*types.JSONRPCResponse, error) {
 	req := types.GetTaskRequest{
-		Id:     uuid.New().String(),
+		Id:     c.idGenerator.Generate(),
 		Method: types.MethodTasksGet,
 		Params: params,
 	}
 
 	var resp types.JSONRPCResponse
-	err := c.sendRequest(req, &resp)
+	err := c.sendRequest(ctx, req.Id, req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -95,13 +120,18 @@ func (c *A2AClient) GetTask(params types.TaskQueryParams) (*types.JSONRPCRespons
 }
 
 func (c *A2AClient) CancelTask(params types.TaskIdParams) (*types.JSONRPCResponse, error) {
+	return c.CancelTaskContext(context.Background(), params)
+}
+
+// CancelTaskContext is like CancelTask, the call and its retries are canceled with the context.
+func (c *A2AClient) CancelTaskContext(ctx context.Context, params types.TaskIdParams) (*types.JSONRPCResponse, error) {
 	req := types.CancelTaskRequest{
-		Id:     uuid.New().String(),
+		Id:     c.idGenerator.Generate(),
 		Method: types.MethodTasksCancel,
 		Params: params,
 	}
 	var resp types.JSONRPCResponse
-	err := c.sendRequest(req, &resp)
+	err := c.sendRequest(ctx, req.Id, req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -109,14 +139,19 @@ func (c *A2AClient) CancelTask(params types.TaskIdParams) (*types.JSONRPCRespons
 }
 
 func (c *A2AClient) SetTaskPushNotificationConfig(params types.TaskPushNotificationConfig) (*types.JSONRPCResponse, error) {
+	return c.SetTaskPushNotificationConfigContext(context.Background(), params)
+}
+
+// SetTaskPushNotificationConfigContext is like SetTaskPushNotificationConfig, the call and its retries are canceled with the context.
+func (c *A2AClient) SetTaskPushNotificationConfigContext(ctx context.Context, params types.TaskPushNotificationConfig) (*types.JSONRPCResponse, error) {
 	req := types.SetTaskPushNotificationConfigRequest{
-		Id:     uuid.New().String(),
+		Id:     c.idGenerator.Generate(),
 		Method: types.MethodPushNotificationSet,
 		Params: params,
 	}
 
 	var resp types.JSONRPCResponse
-	err := c.sendRequest(req, &resp)
+	err := c.sendRequest(ctx, req.Id, req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -124,14 +159,19 @@ func (c *A2AClient) SetTaskPushNotificationConfig(params types.TaskPushNotificat
 }
 
 func (c *A2AClient) GetTaskPushNotificationConfig(params types.TaskIdParams) (*types.JSONRPCResponse, error) {
+	return c.GetTaskPushNotificationConfigContext(context.Background(), params)
+}
+
+// GetTaskPushNotificationConfigContext is like GetTaskPushNotificationConfig, the call and its retries are canceled with the context.
+func (c *A2AClient) GetTaskPushNotificationConfigContext(ctx context.Context, params types.TaskIdParams) (*types.JSONRPCResponse, error) {
 	req := types.GetTaskPushNotificationConfigRequest{
-		Id:     uuid.New().String(),
+		Id:     c.idGenerator.Generate(),
 		Method: types.MethodPushNotificationGet,
 		Params: params,
 	}
 
 	var resp types.JSONRPCResponse
-	err := c.sendRequest(req, &resp)
+	err := c.sendRequest(ctx, req.Id, req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -139,8 +179,11 @@ func (c *A2AClient) GetTaskPushNotificationConfig(params types.TaskIdParams) (*t
 }
 
 func (c *A2AClient) SendMessageStream(param types.MessageSendParam, eventChan chan types.Event) error {
+	if c.err != nil {
+		return c.err
+	}
 	request := types.SendStreamingMessageRequest{
-		Id:      uuid.New().String(),
+		Id:      c.idGenerator.Generate(),
 		JSONRPC: types.Version,
 		Method:  types.MethodMessageStream,
 		Params:  param,
@@ -179,12 +222,15 @@ func (c *A2AClient) SendMessageStream(param types.MessageSendParam, eventChan ch
 	if httpResp.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
 	}
-	return c.processStream(httpReq.Context(), httpResp.Body, eventChan)
+	return c.processStream(httpReq.Context(), request.Id, httpResp.Body, eventChan)
 }
 
 func (c *A2AClient) ResubscribeToTask(params types.TaskIdParams, eventChan chan types.Event) error {
+	if c.err != nil {
+		return c.err
+	}
 	request := types.TaskResubscriptionRequest{
-		Id:      uuid.New().String(),
+		Id:      c.idGenerator.Generate(),
 		JSONRPC: types.Version,
 		Method:  types.MethodTasksResubscribe,
 		Params:  params,
@@ -224,44 +270,88 @@ func (c *A2AClient) ResubscribeToTask(params types.TaskIdParams, eventChan chan
 	if httpResp.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected status code: %d", httpResp.StatusCode)
 	}
-	return c.processStream(httpReq.Context(), httpResp.Body, eventChan)
+	return c.processStream(httpReq.Context(), request.Id, httpResp.Body, eventChan)
 }
 
-func (c *A2AClient) sendRequest(request any, resp *types.JSONRPCResponse) error {
+func (c *A2AClient) sendRequest(ctx context.Context, id string, request any, resp *types.JSONRPCResponse) error {
+	if c.err != nil {
+		return c.err
+	}
 	payload, err := json.Marshal(request)
 	if err != nil {
 		return err
 	}
-	httpReq, err := http.NewRequest(http.MethodPost, c.url, bytes.NewBuffer(payload))
+
+	attempts := c.retryPolicy.attempts(request)
+	for attempt := 1; ; attempt++ {
+		httpResp, err := c.doRequest(ctx, payload)
+		if attempt < attempts {
+			if delay, ok := c.retryPolicy.retryDelay(httpResp, err, attempt); ok {
+				log.Debugf("retrying request to %s in %v (attempt %d/%d)", c.url, delay, attempt+1, attempts)
+				discardResponse(httpResp)
+				if err := wait(ctx, delay); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+		return c.decodeResponse(id, httpResp, resp)
+	}
+}
+
+// doRequest performs a single HTTP attempt with the given JSON-RPC payload. When the
+// server rejects a credential that a middleware can refresh, the request is sent once
+// more with a fresh credential.
+func (c *A2AClient) doRequest(ctx context.Context, payload []byte) (*http.Response, error) {
+	httpResp, callCtx, err := c.send(ctx, payload, false)
+	if err != nil || httpResp.StatusCode != http.StatusUnauthorized || callCtx.Get(middleware.StateRefreshable) != true {
+		return httpResp, err
+	}
+	discardResponse(httpResp)
+	httpResp, _, err = c.send(ctx, payload, true)
+	return httpResp, err
+}
+
+func (c *A2AClient) send(ctx context.Context, payload []byte, unauthorized bool) (*http.Response, web.Context, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewBuffer(payload))
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	ctx := c.createCallContext(httpReq)
+	callCtx := c.createCallContext(httpReq)
+	if unauthorized {
+		callCtx.Set(middleware.StateUnauthorized, true)
+	}
 
-	if err := c.apply(ctx); err != nil {
-		return fmt.Errorf("middleware error: %w", err)
+	if err := c.apply(callCtx); err != nil {
+		return nil, nil, fmt.Errorf("middleware error: %w", err)
 	}
 
 	httpResp, err := c.clint.Do(httpReq)
-	if err != nil {
-		return err
-	}
+	return httpResp, callCtx, err
+}
 
+func (c *A2AClient) decodeResponse(id string, httpResp *http.Response, resp *types.JSONRPCResponse) error {
 	defer func() {
-		err = httpResp.Body.Close()
+		err := httpResp.Body.Close()
 		if err != nil {
 			log.Errorf("Failed to send HTTP request to %s: %v", c.url, err)
 		}
 	}()
 
+	if err := checkResponse(httpResp); err != nil {
+		return err
+	}
 	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
 		return err
 	}
-	return nil
+	return c.checkResponseID(id, resp)
 }
 
-func (c *A2AClient) processStream(ctx context.Context, body io.Reader, eventChan chan types.Event) error {
+func (c *A2AClient) processStream(ctx context.Context, id string, body io.Reader, eventChan chan types.Event) error {
 	scanner := bufio.NewScanner(body)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -272,6 +362,9 @@ func (c *A2AClient) processStream(ctx context.Context, body io.Reader, eventChan
 		if err := json.Unmarshal([]byte(line), &event); err != nil {
 			return fmt.Errorf("failed to decode event: %w", err)
 		}
+		if err := c.checkResponseID(id, &event); err != nil {
+			return err
+		}
 		if event.Error != nil {
 			return fmt.Errorf("a2a error: %s (code: %d)", event.Error.Message, event.Error.Code)
 		}
@@ -15,9 +15,13 @@
 package client
 
 import (
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -368,3 +372,79 @@ func TestA2AClient_Use(t *testing.T) {
 		assert.Len(t, client.middlewares, 3)
 	})
 }
+
+func TestRefreshCredentialOnUnauthorized(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", issued.Add(1)),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+		assert.NoError(t, err)
+	}))
+	defer tokenServer.Close()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(types.JSONRPCResponse{JSONRPC: types.Version, Result: types.Task{Id: "1"}})
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	card := &types.AgentCard{
+		Security: types.SecurityRequirement{{"oauth": []string{}}},
+		SecuritySchemes: map[string]types.SecurityScheme{
+			"oauth": types.OAuth2SecurityScheme{
+				Type:  types.OAUTH2,
+				Flows: types.OAuthFlows{ClientCredentials: &types.ClientCredentialsOAuthFlow{TokenUrl: tokenServer.URL}},
+			},
+		},
+	}
+	client := NewClient(http.DefaultClient, server.URL, WithAgentCard(card))
+	client.Use(middleware.OAuth2ClientCredentials("client", "secret", middleware.WithTokenClient(tokenServer.Client())))
+
+	_, err := client.GetTask(types.TaskQueryParams{Id: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), attempts.Load())
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+type recordingTransport struct {
+	calls atomic.Int32
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.calls.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClientCertificateTransport(t *testing.T) {
+	proxy := func(*http.Request) (*url.URL, error) { return nil, nil }
+	base := &http.Client{Transport: &http.Transport{Proxy: proxy}}
+	mutualTLS, err := NewMutualTLSHTTPClient(base, tls.Certificate{}, nil)
+	require.NoError(t, err)
+	transport, ok := mutualTLS.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.NotNil(t, transport.Proxy, "the settings of the transport are kept")
+	assert.Len(t, transport.TLSClientConfig.Certificates, 1)
+	if config := base.Transport.(*http.Transport).TLSClientConfig; config != nil {
+		assert.Empty(t, config.Certificates, "the base transport is not modified")
+	}
+
+	custom := &recordingTransport{}
+	_, err = NewMutualTLSHTTPClient(&http.Client{Transport: custom}, tls.Certificate{}, nil)
+	require.ErrorIs(t, err, ErrUnsupportedTransport)
+
+	client := NewClient(&http.Client{Transport: custom}, "http://127.0.0.1:1", WithClientCertificate(tls.Certificate{}, nil))
+	_, err = client.GetTask(types.TaskQueryParams{Id: "1"})
+	require.ErrorIs(t, err, ErrUnsupportedTransport)
+	assert.Equal(t, int32(0), custom.calls.Load(), "no request is sent without the certificate")
+}
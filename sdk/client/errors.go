@@ -0,0 +1,245 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxErrorBodySize is the number of body bytes kept in an HTTPError for debugging.
+const maxErrorBodySize = 512
+
+// HTTPError describes an HTTP response that could not be decoded as a JSON-RPC response.
+type HTTPError struct {
+	StatusCode  int    // HTTP status code of the response
+	ContentType string // Content-Type header of the response
+	Body        string // Leading part of the response body, truncated to 512 bytes
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected http response: status %d, content type %q, body %q", e.StatusCode, e.ContentType, e.Body)
+}
+
+// UnauthorizedError is returned when the server answers with 401 Unauthorized.
+type UnauthorizedError struct {
+	*HTTPError
+	Challenges []AuthChallenge // Challenges parsed from the WWW-Authenticate header
+}
+
+func (e *UnauthorizedError) Error() string {
+	if len(e.Challenges) == 0 {
+		return fmt.Sprintf("unauthorized: body %q", e.Body)
+	}
+	schemes := make([]string, 0, len(e.Challenges))
+	for _, challenge := range e.Challenges {
+		schemes = append(schemes, challenge.Scheme)
+	}
+	return fmt.Sprintf("unauthorized: server accepts %s, body %q", strings.Join(schemes, ", "), e.Body)
+}
+
+func (e *UnauthorizedError) Unwrap() error {
+	return e.HTTPError
+}
+
+// NotFoundError is returned when the server answers with 404 Not Found,
+// which usually means the client URL does not point at an A2A endpoint.
+type NotFoundError struct {
+	*HTTPError
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("endpoint not found: body %q", e.Body)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.HTTPError
+}
+
+// RateLimitedError is returned when the server answers with 429 Too Many Requests.
+type RateLimitedError struct {
+	*HTTPError
+	RetryAfter time.Duration // Delay requested by the Retry-After header, zero if absent
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited: retry after %v, body %q", e.RetryAfter, e.Body)
+	}
+	return fmt.Sprintf("rate limited: body %q", e.Body)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.HTTPError
+}
+
+// ServerError is returned when the server answers with a 5xx status code.
+type ServerError struct {
+	*HTTPError
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server error: status %d, body %q", e.StatusCode, e.Body)
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.HTTPError
+}
+
+// AuthChallenge is a single challenge of a WWW-Authenticate header, such as
+// `Bearer realm="agents", error="invalid_token"`.
+type AuthChallenge struct {
+	Scheme string
+	Params map[string]string
+}
+
+// checkResponse classifies a response to a unary call, returning a typed error
+// when the status code is not 200 or the body is not JSON.
+func checkResponse(resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	if resp.StatusCode == http.StatusOK && isJSONContentType(contentType) {
+		return nil
+	}
+
+	httpErr := &HTTPError{
+		StatusCode:  resp.StatusCode,
+		ContentType: contentType,
+		Body:        readBodySnippet(resp.Body),
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return &UnauthorizedError{
+			HTTPError:  httpErr,
+			Challenges: parseWWWAuthenticate(resp.Header.Values("WWW-Authenticate")),
+		}
+	case resp.StatusCode == http.StatusNotFound:
+		return &NotFoundError{HTTPError: httpErr}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return &RateLimitedError{HTTPError: httpErr, RetryAfter: retryAfter}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return &ServerError{HTTPError: httpErr}
+	default:
+		return httpErr
+	}
+}
+
+// isJSONContentType reports whether the media type is JSON. A missing
+// Content-Type is tolerated, as some servers omit it.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// readBodySnippet reads the leading part of the body for error reporting.
+func readBodySnippet(body io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(body, maxErrorBodySize+1))
+	if len(data) > maxErrorBodySize {
+		return string(data[:maxErrorBodySize]) + "..."
+	}
+	return string(data)
+}
+
+// parseWWWAuthenticate parses the challenges of WWW-Authenticate header values
+// as defined by RFC 9110, section 11.6.1.
+func parseWWWAuthenticate(values []string) []AuthChallenge {
+	var challenges []AuthChallenge
+	for _, value := range values {
+		p := &challengeParser{s: value}
+		for {
+			p.skip(" \t,")
+			scheme := p.token()
+			if scheme == "" {
+				break
+			}
+			challenge := AuthChallenge{Scheme: scheme, Params: make(map[string]string)}
+			p.params(challenge.Params)
+			challenges = append(challenges, challenge)
+		}
+	}
+	return challenges
+}
+
+type challengeParser struct {
+	s   string
+	pos int
+}
+
+// params parses the auth-params following a scheme, stopping at the start of the next challenge.
+func (p *challengeParser) params(params map[string]string) {
+	for {
+		p.skip(" \t,")
+		start := p.pos
+		name := p.token()
+		if name == "" {
+			return
+		}
+		p.skip(" \t")
+		if p.pos >= len(p.s) || p.s[p.pos] != '=' {
+			// A token that is not followed by '=' starts the next challenge.
+			p.pos = start
+			return
+		}
+		p.pos++
+		p.skip(" \t")
+		params[strings.ToLower(name)] = p.value()
+	}
+}
+
+func (p *challengeParser) value() string {
+	if p.pos >= len(p.s) || p.s[p.pos] != '"' {
+		return p.token()
+	}
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && p.pos < len(p.s):
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case c == '"':
+			return b.String()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func (p *challengeParser) token() string {
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(" \t,=\"", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *challengeParser) skip(chars string) {
+	for p.pos < len(p.s) && strings.ContainsRune(chars, rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
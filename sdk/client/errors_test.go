@@ -0,0 +1,153 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestSendRequestHTTPErrors(t *testing.T) {
+	testcases := []struct {
+		name        string
+		status      int
+		contentType string
+		header      map[string]string
+		body        string
+		check       func(t *testing.T, err error)
+	}{
+		{
+			name:        "unauthorized",
+			status:      http.StatusUnauthorized,
+			contentType: "text/plain",
+			header:      map[string]string{"WWW-Authenticate": `Bearer realm="agents", error="invalid_token"`},
+			body:        "token expired",
+			check: func(t *testing.T, err error) {
+				var target *UnauthorizedError
+				require.ErrorAs(t, err, &target)
+				require.Len(t, target.Challenges, 1)
+				assert.Equal(t, "Bearer", target.Challenges[0].Scheme)
+				assert.Equal(t, "invalid_token", target.Challenges[0].Params["error"])
+				assert.Equal(t, "token expired", target.Body)
+			},
+		},
+		{
+			name:        "not found html page",
+			status:      http.StatusNotFound,
+			contentType: "text/html",
+			body:        "<html>not found</html>",
+			check: func(t *testing.T, err error) {
+				var target *NotFoundError
+				require.ErrorAs(t, err, &target)
+				assert.Equal(t, "text/html", target.ContentType)
+			},
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			header: map[string]string{"Retry-After": "7"},
+			check: func(t *testing.T, err error) {
+				var target *RateLimitedError
+				require.ErrorAs(t, err, &target)
+				assert.Equal(t, 7*time.Second, target.RetryAfter)
+			},
+		},
+		{
+			name:   "server error with long body",
+			status: http.StatusInternalServerError,
+			body:   strings.Repeat("x", 2*maxErrorBodySize),
+			check: func(t *testing.T, err error) {
+				var target *ServerError
+				require.ErrorAs(t, err, &target)
+				assert.Len(t, target.Body, maxErrorBodySize+len("..."))
+			},
+		},
+		{
+			name:        "success with html body",
+			status:      http.StatusOK,
+			contentType: "text/html",
+			body:        "<html></html>",
+			check: func(t *testing.T, err error) {
+				var target *HTTPError
+				require.ErrorAs(t, err, &target)
+				assert.Equal(t, http.StatusOK, target.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.contentType != "" {
+					w.Header().Set("Content-Type", tc.contentType)
+				}
+				for key, value := range tc.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(http.DefaultClient, server.URL)
+			_, err := client.GetTask(types.TaskQueryParams{Id: "1"})
+			require.Error(t, err)
+			tc.check(t, err)
+		})
+	}
+}
+
+func TestParseWWWAuthenticate(t *testing.T) {
+	testcases := []struct {
+		name   string
+		values []string
+		want   []AuthChallenge
+	}{
+		{
+			name:   "scheme only",
+			values: []string{"Basic"},
+			want:   []AuthChallenge{{Scheme: "Basic", Params: map[string]string{}}},
+		},
+		{
+			name:   "multiple challenges in one header",
+			values: []string{`Basic realm="a, b", Bearer realm="api", scope="read write"`},
+			want: []AuthChallenge{
+				{Scheme: "Basic", Params: map[string]string{"realm": "a, b"}},
+				{Scheme: "Bearer", Params: map[string]string{"realm": "api", "scope": "read write"}},
+			},
+		},
+		{
+			name:   "multiple headers with escaped quotes",
+			values: []string{`Bearer error=invalid_token`, `ApiKey realm="say \"hi\""`},
+			want: []AuthChallenge{
+				{Scheme: "Bearer", Params: map[string]string{"error": "invalid_token"}},
+				{Scheme: "ApiKey", Params: map[string]string{"realm": `say "hi"`}},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, parseWWWAuthenticate(tc.values))
+		})
+	}
+}
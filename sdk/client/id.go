@@ -0,0 +1,90 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// IDGenerator generates the JSON-RPC request ids used by the client.
+type IDGenerator interface {
+	Generate() string
+}
+
+// IDGeneratorFunc is a function type for IDGenerator.
+type IDGeneratorFunc func() string
+
+func (fn IDGeneratorFunc) Generate() string {
+	return fn()
+}
+
+// UUIDGenerator returns a generator of random UUIDs, which is the default.
+func UUIDGenerator() IDGenerator {
+	return IDGeneratorFunc(func() string {
+		return uuid.New().String()
+	})
+}
+
+// NewSequentialIDGenerator returns a generator of increasing numbers starting at 1,
+// each prefixed with the given prefix, for example "gateway-1", "gateway-2".
+// It is safe for concurrent use.
+func NewSequentialIDGenerator(prefix string) IDGenerator {
+	var counter atomic.Uint64
+	return IDGeneratorFunc(func() string {
+		return prefix + strconv.FormatUint(counter.Add(1), 10)
+	})
+}
+
+// WithIDGenerator sets the generator of JSON-RPC request ids.
+func WithIDGenerator(generator IDGenerator) A2AClientOption {
+	return A2AClientOptionFunc(func(client *A2AClient) {
+		client.idGenerator = generator
+	})
+}
+
+// WithStrictIDCheck makes the client reject responses, and stream frames,
+// whose id does not match the id of the request with an *IDMismatchError.
+func WithStrictIDCheck() A2AClientOption {
+	return A2AClientOptionFunc(func(client *A2AClient) {
+		client.strictIDCheck = true
+	})
+}
+
+// IDMismatchError is returned in strict mode when a response id does not match the request id.
+type IDMismatchError struct {
+	Expected string // Id of the request
+	Actual   string // Id carried by the response
+}
+
+func (e *IDMismatchError) Error() string {
+	return fmt.Sprintf("response id %q does not match request id %q", e.Actual, e.Expected)
+}
+
+// checkResponseID verifies the id of a response in strict mode. Error responses
+// without an id are accepted, since the server may be unable to read the request id.
+func (c *A2AClient) checkResponseID(expected string, resp *types.JSONRPCResponse) error {
+	if !c.strictIDCheck || resp.Id == expected {
+		return nil
+	}
+	if resp.Id == "" && resp.Error != nil {
+		return nil
+	}
+	return &IDMismatchError{Expected: expected, Actual: resp.Id}
+}
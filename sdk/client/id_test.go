@@ -0,0 +1,123 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestSequentialIDGenerator(t *testing.T) {
+	generator := NewSequentialIDGenerator("trace-")
+	assert.Equal(t, "trace-1", generator.Generate())
+	assert.Equal(t, "trace-2", generator.Generate())
+}
+
+func TestStrictIDCheck(t *testing.T) {
+	testcases := []struct {
+		name     string
+		options  []A2AClientOption
+		response func(id string) types.JSONRPCResponse
+		wantErr  bool
+	}{
+		{
+			name:    "matching id",
+			options: []A2AClientOption{WithStrictIDCheck()},
+			response: func(id string) types.JSONRPCResponse {
+				return types.JSONRPCResponse{Id: id, JSONRPC: types.Version, Result: types.Task{Id: "1"}}
+			},
+		},
+		{
+			name:    "mismatched id in strict mode",
+			options: []A2AClientOption{WithStrictIDCheck()},
+			response: func(id string) types.JSONRPCResponse {
+				return types.JSONRPCResponse{Id: "other", JSONRPC: types.Version, Result: types.Task{Id: "1"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "mismatched id without strict mode",
+			response: func(id string) types.JSONRPCResponse {
+				return types.JSONRPCResponse{Id: "other", JSONRPC: types.Version, Result: types.Task{Id: "1"}}
+			},
+		},
+		{
+			name:    "error response without id",
+			options: []A2AClientOption{WithStrictIDCheck()},
+			response: func(id string) types.JSONRPCResponse {
+				return types.JSONRPCResponse{JSONRPC: types.Version, Error: types.InternalError()}
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req types.JSONRPCRequest
+				err := json.NewDecoder(r.Body).Decode(&req)
+				assert.NoError(t, err)
+				assert.Equal(t, "req-1", req.Id)
+				w.Header().Set("Content-Type", "application/json")
+				err = json.NewEncoder(w).Encode(tc.response(req.Id))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			options := append([]A2AClientOption{WithIDGenerator(NewSequentialIDGenerator("req-"))}, tc.options...)
+			client := NewClient(http.DefaultClient, server.URL, options...)
+			_, err := client.GetTask(types.TaskQueryParams{Id: "1"})
+			if tc.wantErr {
+				var target *IDMismatchError
+				require.ErrorAs(t, err, &target)
+				assert.Equal(t, "req-1", target.Expected)
+				assert.Equal(t, "other", target.Actual)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestStrictIDCheckStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req types.JSONRPCRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		assert.NoError(t, err)
+		encoder := json.NewEncoder(w)
+		frames := []types.JSONRPCResponse{
+			{Id: req.Id, JSONRPC: types.Version, Result: &types.Task{Id: "1", Kind: types.EventTypeTask}},
+			{Id: "stale", JSONRPC: types.Version, Result: &types.Task{Id: "1", Kind: types.EventTypeTask}},
+		}
+		for _, frame := range frames {
+			assert.NoError(t, encoder.Encode(frame))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(http.DefaultClient, server.URL, WithStrictIDCheck())
+	eventChan := make(chan types.Event, 2)
+	err := client.SendMessageStream(types.MessageSendParam{Message: &types.Message{}}, eventChan)
+	var target *IDMismatchError
+	require.ErrorAs(t, err, &target)
+	assert.Equal(t, "stale", target.Actual)
+	assert.Len(t, eventChan, 1)
+}
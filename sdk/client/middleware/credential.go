@@ -26,6 +26,59 @@ type Credential interface {
 	GetCredentials(securitySchemeName string, context web.Context) (string, error)
 }
 
+// LazyCredential is implemented by credentials obtaining their value on request, such as
+// OAuth2 tokens. Intercept asks them whether they provide the schemes of an alternative to
+// choose it, and only gets the credentials of the alternative it applies.
+type LazyCredential interface {
+	Credential
+	// Provides reports whether credentials can be obtained for the scheme.
+	Provides(securitySchemeName string, context web.Context) bool
+}
+
+// ChainCredentials returns a Credential asking each of the given credentials in turn, and
+// returning the first credentials found for a scheme. It allows alternatives requiring several
+// schemes, such as an OAuth2 token and an API key, to be satisfied by different providers.
+func ChainCredentials(credentials ...Credential) LazyCredential {
+	return chainedCredentials(credentials)
+}
+
+type chainedCredentials []Credential
+
+func (c chainedCredentials) GetCredentials(securitySchemeName string, context web.Context) (string, error) {
+	for _, credential := range c {
+		if lazy, ok := credential.(LazyCredential); ok && !lazy.Provides(securitySchemeName, context) {
+			continue
+		}
+		value, err := credential.GetCredentials(securitySchemeName, context)
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+func (c chainedCredentials) Provides(securitySchemeName string, context web.Context) bool {
+	for _, credential := range c {
+		if provides(credential, securitySchemeName, context) {
+			return true
+		}
+	}
+	return false
+}
+
+// provides reports whether the credential has credentials for the scheme. Credentials that
+// are not lazy are looked up.
+func provides(credential Credential, securitySchemeName string, context web.Context) bool {
+	if lazy, ok := credential.(LazyCredential); ok {
+		return lazy.Provides(securitySchemeName, context)
+	}
+	value, err := credential.GetCredentials(securitySchemeName, context)
+	return err == nil && value != ""
+}
+
 type CredentialKey struct {
 	SessionID          string
 	SecuritySchemeName string
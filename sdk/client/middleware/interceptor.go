@@ -15,45 +15,181 @@
 package middleware
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/yeeaiclub/a2a-go/sdk/types"
 	"github.com/yeeaiclub/a2a-go/sdk/web"
 )
 
+var (
+	// ErrUnsupportedScheme is returned when the agent card declares a security
+	// scheme that the client does not know how to apply.
+	ErrUnsupportedScheme = errors.New("unsupported security scheme")
+	// ErrUnsatisfiedRequirement is returned when no alternative of the security
+	// requirement can be satisfied with the available credentials.
+	ErrUnsatisfiedRequirement = errors.New("no security requirement alternative can be satisfied")
+)
+
+// Intercept returns a middleware that authenticates requests according to the
+// security requirement of the agent card. The requirement lists alternatives, of
+// which the first one whose schemes all have credentials is applied; the request
+// fails with ErrUnsatisfiedRequirement when there is none. A mutualTLS scheme needs no
+// credentials, the client certificate being presented by the transport of the client.
+// Lazy credentials, such as OAuth2 tokens, are only obtained for the chosen alternative.
 func Intercept(credential Credential) web.MiddlewareFunc {
 	return func(next web.HandlerFunc) web.HandlerFunc {
 		return func(ctx web.Context) error {
-			for _, requirement := range ctx.GetSecurityRequirement() {
-				for key := range requirement {
-					credentials, err := credential.GetCredentials(key, ctx)
-					if err != nil {
-						continue
-					}
-					if credentials == "" {
-						continue
-					}
-					scheme := ctx.GetSecuritySchemes(key)
-					if scheme == nil {
-						continue
-					}
-					switch scheme.GetType() {
-					case types.APIKEY:
-						o := scheme.(types.APIKeySecurityScheme)
-						if o.In == "header" {
-							ctx.Request().Header.Set(o.Name, credentials)
-						}
-					case types.HTTP:
-						s := scheme.(types.HTTPAuthSecurityScheme)
-						if strings.ToLower(s.Scheme) == "bearer" {
-							ctx.Request().Header.Set("Authorization", "Bearer "+credentials)
-						}
-					case types.OAUTH2, types.OPENIDConnect:
-						ctx.Request().Header.Set("Authorization", "Bearer "+credentials)
+			requirement := ctx.GetSecurityRequirement()
+			if len(requirement) == 0 {
+				return next(ctx)
+			}
+
+			reasons := make([]string, 0, len(requirement))
+			for i, alternative := range requirement {
+				names, err := checkAlternative(ctx, credential, alternative)
+				if err != nil {
+					reasons = append(reasons, fmt.Sprintf("alternative %d: %v", i, err))
+					continue
+				}
+				ctx.Set(StateSecurityAlternative, alternative)
+				for _, name := range names {
+					if err := applyScheme(ctx, credential, name); err != nil {
+						return fmt.Errorf("security scheme %q: %w", name, err)
 					}
 				}
+				return next(ctx)
 			}
-			return nil
+			return fmt.Errorf("%w: %s", ErrUnsatisfiedRequirement, strings.Join(reasons, "; "))
+		}
+	}
+}
+
+// checkAlternative reports whether the alternative can be satisfied, failing if any of its
+// schemes is undeclared, unsupported or has no credential. It returns the sorted scheme names.
+func checkAlternative(ctx web.Context, credential Credential, alternative map[string][]string) ([]string, error) {
+	names := make([]string, 0, len(alternative))
+	for name := range alternative {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		scheme := ctx.GetSecuritySchemes(name)
+		if scheme == nil {
+			return nil, fmt.Errorf("security scheme %q is not declared", name)
+		}
+		if err := checkScheme(scheme); err != nil {
+			return nil, fmt.Errorf("security scheme %q: %w", name, err)
+		}
+		if _, ok := scheme.(types.MutualTLSSecurityScheme); ok {
+			continue
+		}
+		if lazy, ok := credential.(LazyCredential); ok {
+			if !lazy.Provides(name, ctx) {
+				return nil, fmt.Errorf("no credentials for %q", name)
+			}
+			continue
+		}
+		value, err := credential.GetCredentials(name, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("credentials for %q: %w", name, err)
+		}
+		if value == "" {
+			return nil, fmt.Errorf("no credentials for %q", name)
+		}
+	}
+	return names, nil
+}
+
+// applyScheme gets the credentials of the scheme and places them on the request.
+func applyScheme(ctx web.Context, credential Credential, name string) error {
+	scheme := ctx.GetSecuritySchemes(name)
+	if _, ok := scheme.(types.MutualTLSSecurityScheme); ok {
+		return nil
+	}
+	value, err := credential.GetCredentials(name, ctx)
+	if err != nil {
+		return fmt.Errorf("credentials: %w", err)
+	}
+	if value == "" {
+		return errors.New("no credentials")
+	}
+	return applyCredentials(ctx.Request(), scheme, value)
+}
+
+// checkScheme reports whether the client is able to apply the scheme.
+func checkScheme(scheme types.SecurityScheme) error {
+	switch s := scheme.(type) {
+	case types.APIKeySecurityScheme:
+		if s.Name == "" {
+			return fmt.Errorf("%w: api key without a name", ErrUnsupportedScheme)
+		}
+		if s.In != types.InHeader && s.In != types.InQuery && s.In != types.InCookie {
+			return fmt.Errorf("%w: api key in %q", ErrUnsupportedScheme, s.In)
 		}
+	case types.HTTPAuthSecurityScheme:
+		if scheme := strings.ToLower(s.Scheme); scheme != "bearer" && scheme != "basic" {
+			return fmt.Errorf("%w: http scheme %q", ErrUnsupportedScheme, s.Scheme)
+		}
+	case types.OAuth2SecurityScheme, types.OpenIdConnectSecurityScheme, types.MutualTLSSecurityScheme:
+	default:
+		return fmt.Errorf("%w: type %q", ErrUnsupportedScheme, scheme.GetType())
+	}
+	return nil
+}
+
+// applyCredentials places the credentials on the request as the scheme describes.
+func applyCredentials(req *http.Request, scheme types.SecurityScheme, credentials string) error {
+	switch s := scheme.(type) {
+	case types.APIKeySecurityScheme:
+		return applyAPIKey(req, s, credentials)
+	case types.HTTPAuthSecurityScheme:
+		switch strings.ToLower(s.Scheme) {
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+credentials)
+		case "basic":
+			req.Header.Set("Authorization", "Basic "+basicCredentials(credentials))
+		default:
+			return fmt.Errorf("%w: http scheme %q", ErrUnsupportedScheme, s.Scheme)
+		}
+	case types.OAuth2SecurityScheme, types.OpenIdConnectSecurityScheme:
+		req.Header.Set("Authorization", "Bearer "+credentials)
+	case types.MutualTLSSecurityScheme:
+	default:
+		return fmt.Errorf("%w: type %q", ErrUnsupportedScheme, scheme.GetType())
+	}
+	return nil
+}
+
+func applyAPIKey(req *http.Request, scheme types.APIKeySecurityScheme, key string) error {
+	if scheme.Name == "" {
+		return fmt.Errorf("%w: api key without a name", ErrUnsupportedScheme)
+	}
+	switch scheme.In {
+	case types.InHeader:
+		req.Header.Set(scheme.Name, key)
+	case types.InQuery:
+		query := req.URL.Query()
+		query.Set(scheme.Name, key)
+		req.URL.RawQuery = query.Encode()
+	case types.InCookie:
+		req.AddCookie(&http.Cookie{Name: scheme.Name, Value: key})
+	default:
+		return fmt.Errorf("%w: api key in %q", ErrUnsupportedScheme, scheme.In)
+	}
+	return nil
+}
+
+// basicCredentials encodes "username:password" credentials for the Basic scheme.
+// Credentials without a colon are assumed to be encoded already.
+func basicCredentials(credentials string) string {
+	if !strings.Contains(credentials, ":") {
+		return credentials
 	}
+	return base64.StdEncoding.EncodeToString([]byte(credentials))
 }
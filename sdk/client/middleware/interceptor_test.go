@@ -16,6 +16,7 @@ package middleware
 
 import (
 	"net/http"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +61,8 @@ func TestIntercept(t *testing.T) {
 		setupContext     func() *CallContext
 		setupCredentials func() *MockCredential
 		expectedHeaders  map[string]string
+		expectedQuery    map[string]string
+		expectedCookies  map[string]string
 		expectedError    bool
 	}{
 		{
@@ -101,7 +104,7 @@ func TestIntercept(t *testing.T) {
 			expectedError: false,
 		},
 		{
-			name: "API key security scheme - query (should not set header)",
+			name: "API key security scheme - query",
 			setupContext: func() *CallContext {
 				ctx := NewCallContext(1)
 				ctx.Set("sessionId", "session1")
@@ -123,8 +126,59 @@ func TestIntercept(t *testing.T) {
 				return cred
 			},
 			expectedHeaders: map[string]string{},
+			expectedQuery:   map[string]string{"api_key": "api-token-123"},
 			expectedError:   false,
 		},
+		{
+			name: "API key security scheme - cookie",
+			setupContext: func() *CallContext {
+				ctx := NewCallContext(1)
+				ctx.Set("sessionId", "session1")
+				ctx.SetSecurityConfig(
+					types.SecurityRequirement{{"apiKey": []string{}}},
+					map[string]types.SecurityScheme{
+						"apiKey": types.APIKeySecurityScheme{
+							Type: types.APIKEY,
+							In:   types.InCookie,
+							Name: "session_key",
+						},
+					},
+				)
+				return ctx
+			},
+			setupCredentials: func() *MockCredential {
+				cred := NewMockCredential()
+				cred.SetCredentials("apiKey", "api-token-123")
+				return cred
+			},
+			expectedCookies: map[string]string{"session_key": "api-token-123"},
+			expectedError:   false,
+		},
+		{
+			name: "API key security scheme - unknown location",
+			setupContext: func() *CallContext {
+				ctx := NewCallContext(1)
+				ctx.Set("sessionId", "session1")
+				ctx.SetSecurityConfig(
+					types.SecurityRequirement{{"apiKey": []string{}}},
+					map[string]types.SecurityScheme{
+						"apiKey": types.APIKeySecurityScheme{
+							Type: types.APIKEY,
+							In:   "body",
+							Name: "api_key",
+						},
+					},
+				)
+				return ctx
+			},
+			setupCredentials: func() *MockCredential {
+				cred := NewMockCredential()
+				cred.SetCredentials("apiKey", "api-token-123")
+				return cred
+			},
+			expectedHeaders: map[string]string{},
+			expectedError:   true,
+		},
 		{
 			name: "HTTP Bearer security scheme",
 			setupContext: func() *CallContext {
@@ -152,7 +206,7 @@ func TestIntercept(t *testing.T) {
 			expectedError: false,
 		},
 		{
-			name: "HTTP Basic security scheme (should not set header)",
+			name: "HTTP Basic security scheme",
 			setupContext: func() *CallContext {
 				ctx := NewCallContext(1)
 				ctx.Set("sessionId", "session1")
@@ -169,11 +223,63 @@ func TestIntercept(t *testing.T) {
 			},
 			setupCredentials: func() *MockCredential {
 				cred := NewMockCredential()
-				cred.SetCredentials("basic", "basic-token-789")
+				cred.SetCredentials("basic", "user:pass")
+				return cred
+			},
+			expectedHeaders: map[string]string{
+				"Authorization": "Basic dXNlcjpwYXNz",
+			},
+			expectedError: false,
+		},
+		{
+			name: "HTTP Basic security scheme with encoded credentials",
+			setupContext: func() *CallContext {
+				ctx := NewCallContext(1)
+				ctx.Set("sessionId", "session1")
+				ctx.SetSecurityConfig(
+					types.SecurityRequirement{{"basic": []string{}}},
+					map[string]types.SecurityScheme{
+						"basic": types.HTTPAuthSecurityScheme{
+							Type:   types.HTTP,
+							Scheme: "Basic",
+						},
+					},
+				)
+				return ctx
+			},
+			setupCredentials: func() *MockCredential {
+				cred := NewMockCredential()
+				cred.SetCredentials("basic", "dXNlcjpwYXNz")
+				return cred
+			},
+			expectedHeaders: map[string]string{
+				"Authorization": "Basic dXNlcjpwYXNz",
+			},
+			expectedError: false,
+		},
+		{
+			name: "unsupported HTTP scheme",
+			setupContext: func() *CallContext {
+				ctx := NewCallContext(1)
+				ctx.Set("sessionId", "session1")
+				ctx.SetSecurityConfig(
+					types.SecurityRequirement{{"digest": []string{}}},
+					map[string]types.SecurityScheme{
+						"digest": types.HTTPAuthSecurityScheme{
+							Type:   types.HTTP,
+							Scheme: "Digest",
+						},
+					},
+				)
+				return ctx
+			},
+			setupCredentials: func() *MockCredential {
+				cred := NewMockCredential()
+				cred.SetCredentials("digest", "digest-token")
 				return cred
 			},
 			expectedHeaders: map[string]string{},
-			expectedError:   false,
+			expectedError:   true,
 		},
 		{
 			name: "OAuth2 security scheme",
@@ -257,7 +363,7 @@ func TestIntercept(t *testing.T) {
 			},
 			expectedHeaders: map[string]string{
 				"X-API-Key":     "api-token-123",
-				"Authorization": "Bearer bearer-token-456",
+				"Authorization": "",
 			},
 			expectedError: false,
 		},
@@ -280,7 +386,7 @@ func TestIntercept(t *testing.T) {
 			},
 			setupCredentials: NewMockCredential,
 			expectedHeaders:  map[string]string{},
-			expectedError:    false,
+			expectedError:    true,
 		},
 		{
 			name: "credential error",
@@ -305,7 +411,7 @@ func TestIntercept(t *testing.T) {
 				return cred
 			},
 			expectedHeaders: map[string]string{},
-			expectedError:   false,
+			expectedError:   true,
 		},
 		{
 			name: "security scheme not found",
@@ -324,7 +430,7 @@ func TestIntercept(t *testing.T) {
 				return cred
 			},
 			expectedHeaders: map[string]string{},
-			expectedError:   false,
+			expectedError:   true,
 		},
 		{
 			name: "nil security scheme",
@@ -345,7 +451,7 @@ func TestIntercept(t *testing.T) {
 				return cred
 			},
 			expectedHeaders: map[string]string{},
-			expectedError:   false,
+			expectedError:   true,
 		},
 		{
 			name: "empty credentials",
@@ -370,7 +476,107 @@ func TestIntercept(t *testing.T) {
 				return cred
 			},
 			expectedHeaders: map[string]string{},
-			expectedError:   false,
+			expectedError:   true,
+		},
+		{
+			name: "alternative requires all schemes",
+			setupContext: func() *CallContext {
+				ctx := NewCallContext(1)
+				ctx.SetSecurityConfig(
+					types.SecurityRequirement{
+						{"apiKey": []string{}, "bearer": []string{}},
+						{"basic": []string{}},
+					},
+					map[string]types.SecurityScheme{
+						"apiKey": types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-API-Key"},
+						"bearer": types.HTTPAuthSecurityScheme{Type: types.HTTP, Scheme: "Bearer"},
+						"basic":  types.HTTPAuthSecurityScheme{Type: types.HTTP, Scheme: "Basic"},
+					},
+				)
+				return ctx
+			},
+			setupCredentials: func() *MockCredential {
+				cred := NewMockCredential()
+				cred.SetCredentials("apiKey", "api-token-123")
+				cred.SetCredentials("basic", "dXNlcjpwYXNz")
+				return cred
+			},
+			expectedHeaders: map[string]string{
+				"X-API-Key":     "",
+				"Authorization": "Basic dXNlcjpwYXNz",
+			},
+			expectedError: false,
+		},
+		{
+			name: "alternative with all schemes satisfied",
+			setupContext: func() *CallContext {
+				ctx := NewCallContext(1)
+				ctx.SetSecurityConfig(
+					types.SecurityRequirement{
+						{"apiKey": []string{}, "bearer": []string{}},
+					},
+					map[string]types.SecurityScheme{
+						"apiKey": types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-API-Key"},
+						"bearer": types.HTTPAuthSecurityScheme{Type: types.HTTP, Scheme: "Bearer"},
+					},
+				)
+				return ctx
+			},
+			setupCredentials: func() *MockCredential {
+				cred := NewMockCredential()
+				cred.SetCredentials("apiKey", "api-token-123")
+				cred.SetCredentials("bearer", "bearer-token-456")
+				return cred
+			},
+			expectedHeaders: map[string]string{
+				"X-API-Key":     "api-token-123",
+				"Authorization": "Bearer bearer-token-456",
+			},
+			expectedError: false,
+		},
+		{
+			name: "mutual tls needs no credentials",
+			setupContext: func() *CallContext {
+				ctx := NewCallContext(1)
+				ctx.SetSecurityConfig(
+					types.SecurityRequirement{
+						{"mtls": []string{}, "bearer": []string{}},
+					},
+					map[string]types.SecurityScheme{
+						"mtls":   types.MutualTLSSecurityScheme{Type: types.MUTUALTLS},
+						"bearer": types.HTTPAuthSecurityScheme{Type: types.HTTP, Scheme: "Bearer"},
+					},
+				)
+				return ctx
+			},
+			setupCredentials: func() *MockCredential {
+				cred := NewMockCredential()
+				cred.SetCredentials("bearer", "bearer-token-456")
+				return cred
+			},
+			expectedHeaders: map[string]string{
+				"Authorization": "Bearer bearer-token-456",
+			},
+			expectedError: false,
+		},
+		{
+			name: "empty alternative allows anonymous access",
+			setupContext: func() *CallContext {
+				ctx := NewCallContext(1)
+				ctx.SetSecurityConfig(
+					types.SecurityRequirement{
+						{"apiKey": []string{}},
+						{},
+					},
+					map[string]types.SecurityScheme{
+						"apiKey": types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-API-Key"},
+					},
+				)
+				return ctx
+			},
+			setupCredentials: NewMockCredential,
+			expectedHeaders:  map[string]string{},
+			expectedError:    false,
 		},
 		{
 			name: "HTTP Bearer case insensitive",
@@ -409,8 +615,10 @@ func TestIntercept(t *testing.T) {
 			require.NoError(t, err)
 			ctx.SetRequest(request)
 
+			called := false
 			middleware := Intercept(credential)
 			handler := middleware(func(ctx web.Context) error {
+				called = true
 				return nil
 			})
 
@@ -418,8 +626,10 @@ func TestIntercept(t *testing.T) {
 
 			if tt.expectedError {
 				require.Error(t, err)
+				assert.False(t, called)
 			} else {
 				require.NoError(t, err)
+				assert.True(t, called)
 			}
 
 			for expectedHeader, expectedValue := range tt.expectedHeaders {
@@ -429,9 +639,72 @@ func TestIntercept(t *testing.T) {
 					expectedHeader, expectedValue, actualValue)
 			}
 
-			if len(tt.expectedHeaders) == 0 {
+			for name, expectedValue := range tt.expectedQuery {
+				assert.Equal(t, expectedValue, request.URL.Query().Get(name))
+			}
+
+			for name, expectedValue := range tt.expectedCookies {
+				cookie, err := request.Cookie(name)
+				require.NoError(t, err)
+				assert.Equal(t, expectedValue, cookie.Value)
+			}
+
+			if len(tt.expectedHeaders) == 0 && len(tt.expectedCookies) == 0 {
 				assert.Empty(t, request.Header)
 			}
 		})
 	}
 }
+
+func TestInterceptUnsatisfiedRequirement(t *testing.T) {
+	ctx := NewCallContext(1)
+	ctx.SetSecurityConfig(
+		types.SecurityRequirement{{"apiKey": []string{}}, {"bearer": []string{}}},
+		map[string]types.SecurityScheme{
+			"apiKey": types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-API-Key"},
+		},
+	)
+	request, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	ctx.SetRequest(request)
+
+	err = Intercept(NewMockCredential())(func(ctx web.Context) error { return nil })(ctx)
+	require.ErrorIs(t, err, ErrUnsatisfiedRequirement)
+	assert.Contains(t, err.Error(), `no credentials for "apiKey"`)
+	assert.Contains(t, err.Error(), `security scheme "bearer" is not declared`)
+}
+
+func TestInterceptChainedCredentials(t *testing.T) {
+	var issued atomic.Int32
+	server := newTokenServer(t, 3600, &issued)
+	defer server.Close()
+
+	ctx := NewCallContext(1)
+	ctx.SetSecurityConfig(
+		types.SecurityRequirement{
+			{"oauth": []string{"tasks.admin"}, "partnerKey": nil},
+			{"oauth": []string{"tasks.read"}, "apiKey": nil},
+		},
+		map[string]types.SecurityScheme{
+			"oauth": types.OAuth2SecurityScheme{
+				Type:  types.OAUTH2,
+				Flows: types.OAuthFlows{ClientCredentials: &types.ClientCredentialsOAuthFlow{TokenUrl: server.URL}},
+			},
+			"apiKey":     types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-API-Key"},
+			"partnerKey": types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-Partner-Key"},
+		},
+	)
+	request, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+	ctx.SetRequest(request)
+
+	keys := NewMockCredential()
+	keys.SetCredentials("apiKey", "key")
+	credential := ChainCredentials(NewClientCredentials("client", "secret", WithTokenClient(server.Client())), keys)
+	require.NoError(t, Intercept(credential)(func(ctx web.Context) error { return nil })(ctx))
+
+	assert.Equal(t, "Bearer token-1:tasks.read", request.Header.Get("Authorization"))
+	assert.Equal(t, "key", request.Header.Get("X-API-Key"))
+	assert.Empty(t, request.Header.Get("X-Partner-Key"))
+	assert.Equal(t, int32(1), issued.Load(), "no token is requested for the rejected alternative")
+}
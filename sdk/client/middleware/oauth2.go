@@ -0,0 +1,348 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+	"github.com/yeeaiclub/a2a-go/sdk/web"
+)
+
+const (
+	// StateUnauthorized is set on the context when a request is sent again
+	// because the server rejected the credentials of the previous attempt.
+	StateUnauthorized = "a2a.unauthorized"
+	// StateRefreshable is set on the context by credentials that can obtain
+	// a fresh value when the server rejects the current one.
+	StateRefreshable = "a2a.refreshable"
+	// StateSecurityAlternative is set on the context by Intercept to the alternative of the
+	// security requirement whose credentials are requested, a map of scheme names to scopes.
+	StateSecurityAlternative = "a2a.security_alternative"
+)
+
+// defaultExpiryDelta is how long before its expiry a token is considered expired,
+// so it is not rejected while the request is in flight.
+const defaultExpiryDelta = 10 * time.Second
+
+// defaultTokenTimeout bounds a request to the token endpoint, which is not bound to the
+// context of the callers sharing it.
+const defaultTokenTimeout = 30 * time.Second
+
+// ClientCredentials obtains OAuth2 access tokens with the client credentials grant
+// for the oauth2 security schemes declared by the agent card. Tokens are cached per
+// token URL and scope set until they expire, or until the server rejects them.
+// Concurrent lookups of the same token share a single request to the token endpoint.
+// The client secret is only sent to https token endpoints, which WithTokenURLs can further
+// restrict, as the token URL is taken from the agent card.
+// It implements LazyCredential, so it is used together with Intercept, and with
+// ChainCredentials to combine it with the credentials of other schemes.
+type ClientCredentials struct {
+	client       *http.Client
+	clientID     string
+	clientSecret string
+	expiryDelta  time.Duration
+	tokenTimeout time.Duration
+	tokenURLs    []string // Token endpoints the secret may be sent to, any https endpoint if empty
+	now          func() time.Time
+
+	mu       sync.Mutex
+	tokens   map[string]oauth2Token // token url and scopes -> token
+	inflight map[string]*tokenCall  // token url and scopes -> token request in progress
+}
+
+type tokenCall struct {
+	done  chan struct{}
+	token oauth2Token
+	err   error
+}
+
+type oauth2Token struct {
+	accessToken string
+	expiry      time.Time // zero if the token does not expire
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// NewClientCredentials creates a ClientCredentials for the given client id and secret.
+func NewClientCredentials(clientID, clientSecret string, opts ...ClientCredentialsOption) *ClientCredentials {
+	c := &ClientCredentials{
+		client:       http.DefaultClient,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		expiryDelta:  defaultExpiryDelta,
+		tokenTimeout: defaultTokenTimeout,
+		now:          time.Now,
+		tokens:       make(map[string]oauth2Token),
+		inflight:     make(map[string]*tokenCall),
+	}
+	for _, opt := range opts {
+		opt.Option(c)
+	}
+	return c
+}
+
+// OAuth2ClientCredentials returns a middleware that authenticates requests with
+// tokens obtained by the client credentials grant. For alternatives also requiring
+// other schemes, use Intercept(ChainCredentials(NewClientCredentials(...), other)).
+func OAuth2ClientCredentials(clientID, clientSecret string, opts ...ClientCredentialsOption) web.MiddlewareFunc {
+	return Intercept(NewClientCredentials(clientID, clientSecret, opts...))
+}
+
+// GetCredentials returns an access token for the named scheme when it is an
+// oauth2 scheme with a client credentials flow, and an empty string otherwise.
+// The scopes requested are the ones the chosen alternative of the security requirement
+// lists for the scheme.
+func (c *ClientCredentials) GetCredentials(securitySchemeName string, ctx web.Context) (string, error) {
+	flow := clientCredentialsFlow(securitySchemeName, ctx)
+	if flow == nil {
+		return "", nil
+	}
+	scopes := requiredScopes(ctx, securitySchemeName)
+
+	if ctx.Get(StateUnauthorized) == true {
+		c.Invalidate(flow, scopes)
+	}
+	ctx.Set(StateRefreshable, true)
+
+	reqCtx := context.Background()
+	if req := ctx.Request(); req != nil {
+		reqCtx = req.Context()
+	}
+	return c.Token(reqCtx, flow, scopes)
+}
+
+// Provides reports whether the named scheme is an oauth2 scheme with a client credentials flow.
+func (c *ClientCredentials) Provides(securitySchemeName string, ctx web.Context) bool {
+	return clientCredentialsFlow(securitySchemeName, ctx) != nil
+}
+
+func clientCredentialsFlow(securitySchemeName string, ctx web.Context) *types.ClientCredentialsOAuthFlow {
+	if ctx == nil {
+		return nil
+	}
+	scheme, ok := ctx.GetSecuritySchemes(securitySchemeName).(types.OAuth2SecurityScheme)
+	if !ok {
+		return nil
+	}
+	return scheme.Flows.ClientCredentials
+}
+
+// Token returns a cached access token for the flow and scopes, requesting a new one when needed.
+func (c *ClientCredentials) Token(ctx context.Context, flow *types.ClientCredentialsOAuthFlow, scopes []string) (string, error) {
+	key := tokenKey(flow, scopes)
+
+	c.mu.Lock()
+	if token, ok := c.tokens[key]; ok && c.valid(token) {
+		c.mu.Unlock()
+		return token.accessToken, nil
+	}
+	call := c.startRequest(key, flow.TokenUrl, scopes)
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return "", call.err
+		}
+		return call.token.accessToken, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// startRequest requests a token unless a request is already in progress, and returns the
+// call to wait for. The caller must hold the lock. The request is not bound to the context
+// of the caller, so that a canceled lookup does not fail the lookups sharing the request.
+func (c *ClientCredentials) startRequest(key, tokenURL string, scopes []string) *tokenCall {
+	if call, ok := c.inflight[key]; ok {
+		return call
+	}
+	call := &tokenCall{done: make(chan struct{})}
+	c.inflight[key] = call
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), c.tokenTimeout)
+		call.token, call.err = c.requestToken(ctx, tokenURL, scopes)
+		cancel()
+
+		c.mu.Lock()
+		if call.err == nil {
+			c.tokens[key] = call.token
+		}
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// Invalidate drops the cached token for the flow and scopes, so the next call requests a new one.
+func (c *ClientCredentials) Invalidate(flow *types.ClientCredentialsOAuthFlow, scopes []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tokens, tokenKey(flow, scopes))
+}
+
+func (c *ClientCredentials) valid(token oauth2Token) bool {
+	return token.expiry.IsZero() || c.now().Add(c.expiryDelta).Before(token.expiry)
+}
+
+// requestToken performs the client credentials grant against the token endpoint (RFC 6749, section 4.4).
+func (c *ClientCredentials) requestToken(ctx context.Context, tokenURL string, scopes []string) (oauth2Token, error) {
+	if err := c.checkTokenURL(tokenURL); err != nil {
+		return oauth2Token{}, err
+	}
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauth2Token{}, fmt.Errorf("oauth2: failed to construct token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return oauth2Token{}, fmt.Errorf("oauth2: token request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return oauth2Token{}, fmt.Errorf("oauth2: failed to read token response: %w", err)
+	}
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return oauth2Token{}, fmt.Errorf("oauth2: failed to parse token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || tr.Error != "" {
+		return oauth2Token{}, fmt.Errorf("oauth2: token request rejected (status %d): %s %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+	}
+	if tr.AccessToken == "" {
+		return oauth2Token{}, errors.New("oauth2: token response has no access_token")
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return oauth2Token{}, fmt.Errorf("oauth2: unsupported token type %q", tr.TokenType)
+	}
+
+	token := oauth2Token{accessToken: tr.AccessToken}
+	if tr.ExpiresIn > 0 {
+		token.expiry = c.now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// checkTokenURL reports an error unless the client secret may be sent to the token endpoint:
+// it must use https, and be one of the endpoints set by WithTokenURLs if any.
+func (c *ClientCredentials) checkTokenURL(tokenURL string) error {
+	if tokenURL == "" {
+		return errors.New("oauth2: client credentials flow has no token url")
+	}
+	u, err := url.Parse(tokenURL)
+	if err != nil {
+		return fmt.Errorf("oauth2: invalid token url: %w", err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("oauth2: token url %q does not use https", tokenURL)
+	}
+	if len(c.tokenURLs) > 0 && !slices.Contains(c.tokenURLs, tokenURL) {
+		return fmt.Errorf("oauth2: token url %q is not allowed", tokenURL)
+	}
+	return nil
+}
+
+// requiredScopes returns the scopes that the chosen alternative of the security requirement
+// lists for the scheme. Without a chosen alternative, the first one listing the scheme is used.
+func requiredScopes(ctx web.Context, schemeName string) []string {
+	if alternative, ok := ctx.Get(StateSecurityAlternative).(map[string][]string); ok {
+		return alternative[schemeName]
+	}
+	for _, alternative := range ctx.GetSecurityRequirement() {
+		if scopes, ok := alternative[schemeName]; ok {
+			return scopes
+		}
+	}
+	return nil
+}
+
+func tokenKey(flow *types.ClientCredentialsOAuthFlow, scopes []string) string {
+	sorted := slices.Clone(scopes)
+	slices.Sort(sorted)
+	return flow.TokenUrl + " " + strings.Join(sorted, " ")
+}
+
+// ClientCredentialsOption allows customizing ClientCredentials via functional options.
+type ClientCredentialsOption interface {
+	Option(c *ClientCredentials)
+}
+
+// ClientCredentialsOptionFunc is a function type for ClientCredentialsOption.
+type ClientCredentialsOptionFunc func(c *ClientCredentials)
+
+func (fn ClientCredentialsOptionFunc) Option(c *ClientCredentials) {
+	fn(c)
+}
+
+// WithTokenClient sets the HTTP client used to call the token endpoint.
+func WithTokenClient(client *http.Client) ClientCredentialsOption {
+	return ClientCredentialsOptionFunc(func(c *ClientCredentials) {
+		c.client = client
+	})
+}
+
+// WithTokenURLs restricts the token endpoints the client secret is sent to. Token URLs
+// declared by agent cards that are not listed are refused. By default, any https token
+// endpoint is accepted.
+func WithTokenURLs(urls ...string) ClientCredentialsOption {
+	return ClientCredentialsOptionFunc(func(c *ClientCredentials) {
+		c.tokenURLs = append(c.tokenURLs, urls...)
+	})
+}
+
+// WithTokenTimeout sets how long a request to the token endpoint may take.
+func WithTokenTimeout(timeout time.Duration) ClientCredentialsOption {
+	return ClientCredentialsOptionFunc(func(c *ClientCredentials) {
+		c.tokenTimeout = timeout
+	})
+}
+
+// WithExpiryDelta sets how long before its expiry a cached token is renewed.
+func WithExpiryDelta(delta time.Duration) ClientCredentialsOption {
+	return ClientCredentialsOptionFunc(func(c *ClientCredentials) {
+		c.expiryDelta = delta
+	})
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+	"github.com/yeeaiclub/a2a-go/sdk/web"
+)
+
+func newTokenServer(t *testing.T, expiresIn int64, issued *atomic.Int32) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d:%s", n, r.PostForm.Get("scope")),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+}
+
+func newOAuth2Context(tokenURL string) *CallContext {
+	ctx := NewCallContext(1)
+	ctx.SetSecurityConfig(
+		types.SecurityRequirement{{"oauth": []string{"tasks.read", "tasks.write"}}},
+		map[string]types.SecurityScheme{
+			"oauth": types.OAuth2SecurityScheme{
+				Type: types.OAUTH2,
+				Flows: types.OAuthFlows{
+					ClientCredentials: &types.ClientCredentialsOAuthFlow{TokenUrl: tokenURL},
+				},
+			},
+		},
+	)
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
+	ctx.SetRequest(req)
+	return ctx
+}
+
+func TestClientCredentials(t *testing.T) {
+	testcases := []struct {
+		name       string
+		expiresIn  int64
+		clientID   string
+		advance    time.Duration
+		rejected   bool
+		wantToken  string
+		wantIssued int32
+		wantErr    bool
+	}{
+		{
+			name:       "token is cached",
+			expiresIn:  3600,
+			clientID:   "client",
+			wantToken:  "token-1:tasks.read tasks.write",
+			wantIssued: 1,
+		},
+		{
+			name:       "expired token is renewed",
+			expiresIn:  60,
+			clientID:   "client",
+			advance:    time.Minute,
+			wantToken:  "token-2:tasks.read tasks.write",
+			wantIssued: 2,
+		},
+		{
+			name:       "rejected token is renewed",
+			expiresIn:  3600,
+			clientID:   "client",
+			rejected:   true,
+			wantToken:  "token-2:tasks.read tasks.write",
+			wantIssued: 2,
+		},
+		{
+			name:     "invalid client",
+			clientID: "unknown",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var issued atomic.Int32
+			server := newTokenServer(t, tc.expiresIn, &issued)
+			defer server.Close()
+
+			now := time.Now()
+			credentials := NewClientCredentials(tc.clientID, "secret", WithTokenClient(server.Client()))
+			credentials.now = func() time.Time { return now }
+
+			token, err := credentials.GetCredentials("oauth", newOAuth2Context(server.URL))
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "token-1:tasks.read tasks.write", token)
+
+			now = now.Add(tc.advance)
+			ctx := newOAuth2Context(server.URL)
+			if tc.rejected {
+				ctx.Set(StateUnauthorized, true)
+			}
+			token, err = credentials.GetCredentials("oauth", ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantToken, token)
+			assert.Equal(t, tc.wantIssued, issued.Load())
+			assert.Equal(t, true, ctx.Get(StateRefreshable))
+		})
+	}
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var issued atomic.Int32
+	server := newTokenServer(t, 3600, &issued)
+	defer server.Close()
+
+	ctx := newOAuth2Context(server.URL)
+	handler := OAuth2ClientCredentials("client", "secret", WithTokenClient(server.Client()))(func(ctx web.Context) error {
+		return nil
+	})
+	require.NoError(t, handler(ctx))
+	assert.Equal(t, "Bearer token-1:tasks.read tasks.write", ctx.Request().Header.Get("Authorization"))
+}
+
+func TestClientCredentialsScopesOfAlternative(t *testing.T) {
+	var issued atomic.Int32
+	server := newTokenServer(t, 3600, &issued)
+	defer server.Close()
+
+	ctx := newOAuth2Context(server.URL)
+	ctx.SetSecurityConfig(types.SecurityRequirement{
+		{"oauth": []string{"tasks.read"}},
+		{"oauth": []string{"tasks.admin"}, "apiKey": nil},
+	}, ctx.SecuritySchemes)
+	credentials := NewClientCredentials("client", "secret", WithTokenClient(server.Client()))
+
+	token, err := credentials.GetCredentials("oauth", ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1:tasks.read", token, "first alternative listing the scheme")
+
+	ctx.Set(StateSecurityAlternative, ctx.GetSecurityRequirement()[1])
+	token, err = credentials.GetCredentials("oauth", ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-2:tasks.admin", token, "scopes of the chosen alternative only")
+}
+
+func TestClientCredentialsConcurrentTokens(t *testing.T) {
+	var issued atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		if r.PostForm.Get("scope") == "slow" {
+			<-release
+		}
+		n := issued.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d:%s", n, r.PostForm.Get("scope")),
+			"expires_in":   3600,
+		})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	credentials := NewClientCredentials("client", "secret", WithTokenClient(server.Client()))
+	flow := &types.ClientCredentialsOAuthFlow{TokenUrl: server.URL}
+	token, err := credentials.Token(context.Background(), flow, []string{"fast"})
+	require.NoError(t, err)
+	assert.Equal(t, "token-1:fast", token)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 2)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens[i], _ = credentials.Token(context.Background(), flow, []string{"slow"})
+		}()
+	}
+	require.Eventually(t, func() bool {
+		credentials.mu.Lock()
+		defer credentials.mu.Unlock()
+		return len(credentials.inflight) == 1
+	}, time.Second, time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		token, err := credentials.Token(context.Background(), flow, []string{"fast"})
+		assert.NoError(t, err)
+		assert.Equal(t, "token-1:fast", token)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a cached token waits for the request of another token")
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = credentials.Token(canceled, flow, []string{"slow"})
+	require.ErrorIs(t, err, context.Canceled)
+
+	release <- struct{}{}
+	wg.Wait()
+	assert.Equal(t, []string{"token-2:slow", "token-2:slow"}, tokens, "concurrent lookups share a request")
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestClientCredentialsTokenURL(t *testing.T) {
+	var issued atomic.Int32
+	server := newTokenServer(t, 3600, &issued)
+	defer server.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the client secret is sent over plain http")
+	}))
+	defer plain.Close()
+
+	testcases := []struct {
+		name     string
+		tokenURL string
+		options  []ClientCredentialsOption
+		wantErr  string
+	}{
+		{name: "https endpoint", tokenURL: server.URL},
+		{name: "plain http endpoint", tokenURL: plain.URL, wantErr: "does not use https"},
+		{name: "pinned endpoint", tokenURL: server.URL, options: []ClientCredentialsOption{WithTokenURLs(server.URL)}},
+		{
+			name:     "endpoint not pinned",
+			tokenURL: server.URL + "/collect",
+			options:  []ClientCredentialsOption{WithTokenURLs(server.URL)},
+			wantErr:  "is not allowed",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			issued.Store(0)
+			options := append([]ClientCredentialsOption{WithTokenClient(server.Client())}, tc.options...)
+			credentials := NewClientCredentials("client", "secret", options...)
+
+			token, err := credentials.GetCredentials("oauth", newOAuth2Context(tc.tokenURL))
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				assert.Equal(t, int32(0), issued.Load())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "token-1:tasks.read tasks.write", token)
+		})
+	}
+}
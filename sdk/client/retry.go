@@ -0,0 +1,189 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+	defaultMultiplier     = 2.0
+	defaultJitter         = 0.2
+
+	// maxDrainBytes bounds how much of a discarded response body is read
+	// so the underlying connection can be reused.
+	maxDrainBytes = 4 << 10
+)
+
+// RetryPolicy controls how the client retries unary calls that failed with a
+// transient error. Only idempotent methods (tasks/get and push notification
+// config reads) are retried, unless RetryMessageSend is enabled.
+type RetryPolicy struct {
+	MaxAttempts          int           // Total number of attempts, including the first one
+	InitialBackoff       time.Duration // Delay before the first retry
+	MaxBackoff           time.Duration // Upper bound for a single delay, including Retry-After
+	Multiplier           float64       // Growth factor applied to the delay after each attempt
+	Jitter               float64       // Fraction of the delay, in [0, 1], that is randomized
+	RetryableStatusCodes []int         // HTTP status codes that are considered transient
+
+	// RetryMessageSend allows message/send to be retried. It only applies to
+	// messages that carry a MessageID, so the server can de-duplicate them.
+	RetryMessageSend bool
+}
+
+// DefaultRetryPolicy returns a policy with three attempts, exponential backoff
+// starting at 200ms and retries on 429, 502, 503 and 504 responses.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultMaxAttempts,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultMultiplier,
+		Jitter:         defaultJitter,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy enables retries of unary calls with the given policy.
+func WithRetryPolicy(policy RetryPolicy) A2AClientOption {
+	return A2AClientOptionFunc(func(client *A2AClient) {
+		client.retryPolicy = &policy
+	})
+}
+
+// attempts returns the number of attempts allowed for the request.
+func (p *RetryPolicy) attempts(request any) int {
+	if p == nil || p.MaxAttempts <= 1 {
+		return 1
+	}
+	switch req := request.(type) {
+	case types.GetTaskRequest, types.GetTaskPushNotificationConfigRequest:
+		return p.MaxAttempts
+	case types.SendMessageRequest:
+		if p.RetryMessageSend && req.Params.Message != nil && req.Params.Message.MessageID != "" {
+			return p.MaxAttempts
+		}
+	}
+	return 1
+}
+
+// retryDelay reports whether the outcome of an attempt should be retried and how long to wait before doing so.
+func (p *RetryPolicy) retryDelay(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		return p.backoff(attempt), isTransientError(err)
+	}
+	if !slices.Contains(p.RetryableStatusCodes, resp.StatusCode) {
+		return 0, false
+	}
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return p.backoff(attempt), true
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		// The server asked us to wait longer than we are willing to, give up.
+		return 0, false
+	}
+	return delay, true
+}
+
+// backoff computes the exponential delay before the given retry attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := min(p.Jitter, 1)
+		// Spread the delay over [delay*(1-jitter), delay*(1+jitter)) to avoid retry storms.
+		delay *= 1 - jitter + 2*jitter*rand.Float64() //nolint:gosec // jitter does not need a secure source
+	}
+	return time.Duration(delay)
+}
+
+// parseRetryAfter parses a Retry-After header value, given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
+
+// isTransientError reports whether a transport error is worth retrying,
+// such as a connection reset by an intermediate proxy or a timeout.
+func isTransientError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// wait waits for the delay before a retry, returning the error of the context if it is done first.
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discardResponse drains and closes the body of a response that will not be decoded.
+func discardResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainBytes)
+	_ = resp.Body.Close()
+}
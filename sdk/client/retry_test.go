@@ -0,0 +1,228 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 10 * time.Millisecond
+	return policy
+}
+
+func TestSendRequestRetry(t *testing.T) {
+	testcases := []struct {
+		name         string
+		policy       RetryPolicy
+		failures     int
+		status       int
+		retryAfter   string
+		call         func(client *A2AClient) (*types.JSONRPCResponse, error)
+		wantAttempts int32
+	}{
+		{
+			name:     "get task retried until success",
+			policy:   testRetryPolicy(),
+			failures: 2,
+			status:   http.StatusServiceUnavailable,
+			call: func(client *A2AClient) (*types.JSONRPCResponse, error) {
+				return client.GetTask(types.TaskQueryParams{Id: "1"})
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:       "retry after in seconds is honoured",
+			policy:     testRetryPolicy(),
+			failures:   1,
+			status:     http.StatusTooManyRequests,
+			retryAfter: "0",
+			call: func(client *A2AClient) (*types.JSONRPCResponse, error) {
+				return client.GetTaskPushNotificationConfig(types.TaskIdParams{Id: "1"})
+			},
+			wantAttempts: 2,
+		},
+		{
+			name:       "retry after beyond max backoff is not retried",
+			policy:     testRetryPolicy(),
+			failures:   1,
+			status:     http.StatusServiceUnavailable,
+			retryAfter: "120",
+			call: func(client *A2AClient) (*types.JSONRPCResponse, error) {
+				return client.GetTask(types.TaskQueryParams{Id: "1"})
+			},
+			wantAttempts: 1,
+		},
+		{
+			name:     "non retryable status code",
+			policy:   testRetryPolicy(),
+			failures: 1,
+			status:   http.StatusBadRequest,
+			call: func(client *A2AClient) (*types.JSONRPCResponse, error) {
+				return client.GetTask(types.TaskQueryParams{Id: "1"})
+			},
+			wantAttempts: 1,
+		},
+		{
+			name:     "cancel task is not idempotent",
+			policy:   testRetryPolicy(),
+			failures: 1,
+			status:   http.StatusBadGateway,
+			call: func(client *A2AClient) (*types.JSONRPCResponse, error) {
+				return client.CancelTask(types.TaskIdParams{Id: "1"})
+			},
+			wantAttempts: 1,
+		},
+		{
+			name:     "message send without opt in",
+			policy:   testRetryPolicy(),
+			failures: 1,
+			status:   http.StatusBadGateway,
+			call: func(client *A2AClient) (*types.JSONRPCResponse, error) {
+				return client.SendMessage(types.MessageSendParam{Message: &types.Message{MessageID: "m1"}})
+			},
+			wantAttempts: 1,
+		},
+		{
+			name: "message send with opt in and message id",
+			policy: func() RetryPolicy {
+				policy := testRetryPolicy()
+				policy.RetryMessageSend = true
+				return policy
+			}(),
+			failures: 1,
+			status:   http.StatusBadGateway,
+			call: func(client *A2AClient) (*types.JSONRPCResponse, error) {
+				return client.SendMessage(types.MessageSendParam{Message: &types.Message{MessageID: "m1"}})
+			},
+			wantAttempts: 2,
+		},
+		{
+			name: "message send with opt in but no message id",
+			policy: func() RetryPolicy {
+				policy := testRetryPolicy()
+				policy.RetryMessageSend = true
+				return policy
+			}(),
+			failures: 1,
+			status:   http.StatusBadGateway,
+			call: func(client *A2AClient) (*types.JSONRPCResponse, error) {
+				return client.SendMessage(types.MessageSendParam{Message: &types.Message{}})
+			},
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) <= int32(tc.failures) {
+					if tc.retryAfter != "" {
+						w.Header().Set("Retry-After", tc.retryAfter)
+					}
+					w.WriteHeader(tc.status)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				err := json.NewEncoder(w).Encode(types.JSONRPCResponse{JSONRPC: types.Version, Result: types.Task{Id: "1"}})
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client := NewClient(http.DefaultClient, server.URL, WithRetryPolicy(tc.policy))
+			_, _ = tc.call(client)
+			assert.Equal(t, tc.wantAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestSendRequestRetryCanceled(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Minute
+	policy.MaxBackoff = time.Minute
+	client := NewClient(http.DefaultClient, server.URL, WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetTaskContext(ctx, types.TaskQueryParams{Id: "1"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second, "does not sleep through the backoff")
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(1)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.Less(t, delay, 150*time.Millisecond)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "empty", value: "", ok: false},
+		{name: "seconds", value: "3", want: 3 * time.Second, ok: true},
+		{name: "negative seconds", value: "-1", ok: false},
+		{name: "http date", value: now.Add(5 * time.Second).Format(http.TimeFormat), want: 5 * time.Second, ok: true},
+		{name: "past http date", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, ok: true},
+		{name: "garbage", value: "soon", ok: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tc.value, now)
+			require.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrUnsupportedTransport is returned when a client certificate cannot be set on the transport of an HTTP client.
+var ErrUnsupportedTransport = errors.New("cannot set a client certificate on the transport")
+
+// clientCertificate is the certificate the client presents to agents requiring mutual TLS.
+type clientCertificate struct {
+	cert    tls.Certificate
+	rootCAs *x509.CertPool
+}
+
+// NewMutualTLSHTTPClient returns a copy of the base client presenting the certificate to
+// servers that request one, and trusting the given authorities, or the system ones if rootCAs is nil.
+// The transport of the base client must be nil or an *http.Transport; it is cloned, not modified.
+// For another transport, ErrUnsupportedTransport is returned: configure the certificate on it instead.
+func NewMutualTLSHTTPClient(base *http.Client, cert tls.Certificate, rootCAs *x509.CertPool) (*http.Client, error) {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	var transport *http.Transport
+	switch t := base.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("%w %T", ErrUnsupportedTransport, base.Transport)
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+	config.Certificates = []tls.Certificate{cert}
+	if rootCAs != nil {
+		config.RootCAs = rootCAs
+	}
+	transport.TLSClientConfig = config
+
+	client := *base
+	client.Transport = transport
+	return &client, nil
+}
+
+// WithClientCertificate configures the client to present the certificate when the agent card
+// declares a mutualTLS security scheme, or when the client has no agent card. The authorities
+// verify the server certificate, the system ones being used if rootCAs is nil. If the certificate
+// cannot be set on the HTTP client, see NewMutualTLSHTTPClient, every call returns the error.
+func WithClientCertificate(cert tls.Certificate, rootCAs *x509.CertPool) A2AClientOption {
+	return A2AClientOptionFunc(func(client *A2AClient) {
+		client.certificate = &clientCertificate{cert: cert, rootCAs: rootCAs}
+	})
+}
@@ -23,6 +23,7 @@ import (
 )
 
 func TestNewRequestContext(t *testing.T) {
+	blocking := true
 	testcases := []struct {
 		name      string
 		taskId    string
@@ -38,7 +39,7 @@ func TestNewRequestContext(t *testing.T) {
 			params: types.MessageSendParam{
 				Configuration: &types.MessageSendConfiguration{
 					AcceptedOutputModes:    []string{"text", "markdown"},
-					Blocking:               true,
+					Blocking:               &blocking,
 					HistoryLength:          10,
 					PushNotificationConfig: &types.PushNotificationConfig{},
 				},
@@ -71,7 +72,7 @@ func TestNewRequestContext(t *testing.T) {
 				Params: types.MessageSendParam{
 					Configuration: &types.MessageSendConfiguration{
 						AcceptedOutputModes:    []string{"text", "markdown"},
-						Blocking:               true,
+						Blocking:               &blocking,
 						HistoryLength:          10,
 						PushNotificationConfig: &types.PushNotificationConfig{},
 					},
@@ -0,0 +1,147 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// WithAuthenticator registers the authenticator for the named security scheme of the agent card.
+// Once an authenticator is registered, every JSON-RPC request must satisfy the security
+// requirement of the card, and the authenticated user is available from CallContext.GetUser.
+func WithAuthenticator(schemeName string, authenticator auth.Authenticator) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		if server.authenticators == nil {
+			server.authenticators = make(map[string]auth.Authenticator)
+		}
+		server.authenticators[schemeName] = authenticator
+	})
+}
+
+// authenticate checks the request against the security requirement of the card. The requirement
+// lists alternatives, and the request is authenticated by the first alternative whose schemes all
+// accept its credentials. The user is the one identified by the first scheme of that alternative.
+func (s *Server) authenticate(r *http.Request, card types.AgentCard) (auth.User, error) {
+	if len(s.authenticators) == 0 || len(card.Security) == 0 {
+		return auth.AnonymousUser{}, nil
+	}
+
+	var errs []error
+	for _, alternative := range card.Security {
+		user, err := s.authenticateAlternative(r, card, alternative)
+		if err == nil {
+			return user, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (s *Server) authenticateAlternative(r *http.Request, card types.AgentCard, alternative map[string][]string) (auth.User, error) {
+	names := make([]string, 0, len(alternative))
+	for name := range alternative {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var user auth.User = auth.AnonymousUser{}
+	for i, name := range names {
+		scheme := card.SecuritySchemes[name]
+		if scheme == nil {
+			return nil, fmt.Errorf("security scheme %q is not declared", name)
+		}
+		authenticator, ok := s.authenticators[name]
+		if !ok {
+			return nil, fmt.Errorf("no authenticator for security scheme %q", name)
+		}
+		u, err := authenticator.Authenticate(r, scheme, alternative[name])
+		if err != nil {
+			return nil, fmt.Errorf("security scheme %q: %w", name, err)
+		}
+		if u == nil {
+			return nil, fmt.Errorf("security scheme %q: %w", name, auth.ErrInvalidCredentials)
+		}
+		if i == 0 {
+			user = u
+		}
+	}
+	return user, nil
+}
+
+// sendUnauthorized writes a 401 response challenging the client with the schemes of the card.
+func (s *Server) sendUnauthorized(w http.ResponseWriter, card types.AgentCard, err error) {
+	for _, challenge := range challenges(card, errors.Is(err, auth.ErrInvalidCredentials)) {
+		w.Header().Add("WWW-Authenticate", challenge)
+	}
+	message := "authentication required"
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		message = "invalid credentials"
+	}
+	log.Debugf("request rejected: %v", err)
+	http.Error(w, message, http.StatusUnauthorized)
+}
+
+// challenges builds the WWW-Authenticate challenges of the schemes referenced by the security requirement.
+func challenges(card types.AgentCard, invalid bool) []string {
+	realm := fmt.Sprintf("realm=%q", card.Name)
+	seen := make(map[string]bool)
+	var result []string
+	for _, alternative := range card.Security {
+		names := make([]string, 0, len(alternative))
+		for name := range alternative {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var challenge string
+			switch scheme := card.SecuritySchemes[name].(type) {
+			case types.HTTPAuthSecurityScheme:
+				challenge = capitalize(scheme.Scheme) + " " + realm
+			case types.OAuth2SecurityScheme, types.OpenIdConnectSecurityScheme:
+				challenge = "Bearer " + realm
+				if scopes := alternative[name]; len(scopes) > 0 {
+					challenge += fmt.Sprintf(", scope=%q", strings.Join(scopes, " "))
+				}
+			case types.APIKeySecurityScheme:
+				challenge = fmt.Sprintf("ApiKey %s, in=%q, name=%q", realm, scheme.In, scheme.Name)
+			default:
+				continue
+			}
+			if invalid && strings.HasPrefix(challenge, "Bearer ") {
+				challenge += `, error="invalid_token"`
+			}
+			if !seen[challenge] {
+				seen[challenge] = true
+				result = append(result, challenge)
+			}
+		}
+	}
+	return result
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
+}
@@ -0,0 +1,253 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+type userExecutor struct {
+	user auth.User
+}
+
+func (e *userExecutor) Execute(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	e.user = requestContext.CallContext.GetUser()
+	u := updater.NewTaskUpdater(queue, requestContext.TaskId, requestContext.ContextId)
+	u.Complete()
+	return nil
+}
+
+func (e *userExecutor) Cancel(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	return nil
+}
+
+func newSecuredCard() types.AgentCard {
+	card := mockAgentCard
+	card.Security = types.SecurityRequirement{
+		{"bearer": []string{}},
+		{"apiKey": []string{}, "basic": []string{}},
+	}
+	card.SecuritySchemes = map[string]types.SecurityScheme{
+		"bearer": types.HTTPAuthSecurityScheme{Type: types.HTTP, Scheme: "bearer"},
+		"basic":  types.HTTPAuthSecurityScheme{Type: types.HTTP, Scheme: "basic"},
+		"apiKey": types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-API-Key"},
+	}
+	return card
+}
+
+func TestServerAuthentication(t *testing.T) {
+	bearer := auth.NewBearerAuthenticator(func(ctx context.Context, token string, scopes []string) (auth.User, error) {
+		if token != "valid-token" {
+			return nil, auth.ErrInvalidCredentials
+		}
+		return auth.BasicUser{Name: "token-user"}, nil
+	})
+	apiKey := auth.NewAPIKeyAuthenticator(func(ctx context.Context, key string) (auth.User, error) {
+		if key != "valid-key" {
+			return nil, auth.ErrInvalidCredentials
+		}
+		return auth.BasicUser{Name: "key-user"}, nil
+	})
+	basic := auth.NewBasicAuthenticator(func(ctx context.Context, username, password string) (auth.User, error) {
+		if password != "secret" {
+			return nil, auth.ErrInvalidCredentials
+		}
+		return auth.BasicUser{Name: username}, nil
+	})
+
+	testcases := []struct {
+		name          string
+		card          types.AgentCard
+		options       []ServerConfigOption
+		header        map[string]string
+		basic         []string
+		wantStatus    int
+		wantUser      string
+		wantChallenge []string
+	}{
+		{
+			name:       "no authenticator configured",
+			card:       newSecuredCard(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "card without security",
+			card:       mockAgentCard,
+			options:    []ServerConfigOption{WithAuthenticator("bearer", bearer)},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "valid bearer token",
+			card:       newSecuredCard(),
+			options:    []ServerConfigOption{WithAuthenticator("bearer", bearer)},
+			header:     map[string]string{"Authorization": "Bearer valid-token"},
+			wantStatus: http.StatusOK,
+			wantUser:   "token-user",
+		},
+		{
+			name:       "api key and basic alternative",
+			card:       newSecuredCard(),
+			options:    []ServerConfigOption{WithAuthenticator("bearer", bearer), WithAuthenticator("apiKey", apiKey), WithAuthenticator("basic", basic)},
+			header:     map[string]string{"X-API-Key": "valid-key"},
+			basic:      []string{"alice", "secret"},
+			wantStatus: http.StatusOK,
+			wantUser:   "key-user",
+		},
+		{
+			name:          "api key without basic",
+			card:          newSecuredCard(),
+			options:       []ServerConfigOption{WithAuthenticator("bearer", bearer), WithAuthenticator("apiKey", apiKey), WithAuthenticator("basic", basic)},
+			header:        map[string]string{"X-API-Key": "valid-key"},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: []string{`Bearer realm="test agent"`, `ApiKey realm="test agent", in="header", name="X-API-Key"`, `Basic realm="test agent"`},
+		},
+		{
+			name:          "missing credentials",
+			card:          newSecuredCard(),
+			options:       []ServerConfigOption{WithAuthenticator("bearer", bearer)},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: []string{`Bearer realm="test agent"`, `ApiKey realm="test agent", in="header", name="X-API-Key"`, `Basic realm="test agent"`},
+		},
+		{
+			name:          "invalid bearer token",
+			card:          newSecuredCard(),
+			options:       []ServerConfigOption{WithAuthenticator("bearer", bearer)},
+			header:        map[string]string{"Authorization": "Bearer expired"},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: []string{`Bearer realm="test agent", error="invalid_token"`, `ApiKey realm="test agent", in="header", name="X-API-Key"`, `Basic realm="test agent"`},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := tasks.NewInMemoryTaskStore()
+			executor := &userExecutor{}
+			handler := NewDefaultHandler(store, executor, WithQueueManager(QueueManger{}))
+			server := NewServer("/card", "/", tc.card, handler, tc.options...)
+
+			request := types.JSONRPCRequest{
+				Id:     "1",
+				Method: types.MethodMessageSend,
+				Params: types.MessageSendParam{
+					Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User},
+				},
+			}
+			body, err := json.Marshal(request)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body))
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+			if tc.basic != nil {
+				req.SetBasicAuth(tc.basic[0], tc.basic[1])
+			}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+			if tc.wantStatus != http.StatusOK {
+				assert.Equal(t, tc.wantChallenge, w.Header().Values("WWW-Authenticate"))
+				assert.Nil(t, executor.user)
+				return
+			}
+			require.NotNil(t, executor.user)
+			assert.Equal(t, tc.wantUser != "", executor.user.IsAuthenticated())
+			assert.Equal(t, tc.wantUser, executor.user.UserName())
+		})
+	}
+}
+
+func TestGetExtendedAgentCard(t *testing.T) {
+	bearer := auth.NewBearerAuthenticator(func(ctx context.Context, token string, scopes []string) (auth.User, error) {
+		if token != "valid-token" {
+			return nil, auth.ErrInvalidCredentials
+		}
+		return auth.BasicUser{Name: "token-user"}, nil
+	})
+	extended := newSecuredCard()
+	extended.Skills = append(extended.Skills, types.AgentSkill{ID: "internal", Name: "internal skill"})
+
+	testcases := []struct {
+		name       string
+		options    []ServerConfigOption
+		header     string
+		wantStatus int
+	}{
+		{
+			name:       "authenticated caller",
+			options:    []ServerConfigOption{WithAuthenticator("bearer", bearer), WithExtendedAgentCard(extended)},
+			header:     "Bearer valid-token",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing credentials",
+			options:    []ServerConfigOption{WithAuthenticator("bearer", bearer), WithExtendedAgentCard(extended)},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no authenticator configured",
+			options:    []ServerConfigOption{WithExtendedAgentCard(extended)},
+			header:     "Bearer valid-token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no extended card",
+			options:    []ServerConfigOption{WithAuthenticator("bearer", bearer)},
+			header:     "Bearer valid-token",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+			server := NewServer("/card", "/", newSecuredCard(), handler, tc.options...)
+
+			req := httptest.NewRequest(http.MethodGet, types.ExtendedAgentCardPath, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			server.handleGetExtendedAgentCard(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+			card, err := server.agentCard(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, server.extendedCard != nil, card.SupportsAuthenticatedExtendedCard)
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var served struct {
+				Skills []types.AgentSkill `json:"skills"`
+			}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&served))
+			assert.Equal(t, extended.Skills, served.Skills)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/yeeaiclub/a2a-go/internal/errs"
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+	"github.com/yeeaiclub/a2a-go/sdk/server"
+	"github.com/yeeaiclub/a2a-go/sdk/server/scheduler"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/manager"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// ErrPermissionDenied is returned by the handler when an operation is not authorized.
+var ErrPermissionDenied = errs.ErrPermissionDenied
+
+// AuthorizationRequest describes an operation the handler is about to perform.
+type AuthorizationRequest struct {
+	User      auth.User // User performing the operation, never nil
+	Method    string    // JSON-RPC method, such as types.MethodTasksCancel
+	Skill     string    // Skill targeted by the message, empty if not specified
+	TaskId    string    // Task the operation applies to, empty for a new task
+	TaskOwner string    // Name of the user who created the task, empty if not recorded
+}
+
+// Authorizer decides whether an operation is allowed. It returns nil to allow it.
+type Authorizer interface {
+	Authorize(ctx context.Context, request AuthorizationRequest) error
+}
+
+// AuthorizerFunc is a function type for Authorizer.
+type AuthorizerFunc func(ctx context.Context, request AuthorizationRequest) error
+
+func (fn AuthorizerFunc) Authorize(ctx context.Context, request AuthorizationRequest) error {
+	return fn(ctx, request)
+}
+
+// AllOf returns an Authorizer allowing an operation only if all the authorizers allow it.
+func AllOf(authorizers ...Authorizer) Authorizer {
+	return AuthorizerFunc(func(ctx context.Context, request AuthorizationRequest) error {
+		for _, authorizer := range authorizers {
+			if err := authorizer.Authorize(ctx, request); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rule allows the users it lists to call the methods it lists on the skills it lists.
+// An empty list matches anything, except Users which matches any authenticated user.
+type Rule struct {
+	Methods        []string // JSON-RPC methods
+	Skills         []string // Skill ids
+	Users          []string // User names
+	AllowAnonymous bool     // Whether unauthenticated users match the rule
+}
+
+func (r Rule) matches(request AuthorizationRequest) bool {
+	if len(r.Methods) > 0 && !slices.Contains(r.Methods, request.Method) {
+		return false
+	}
+	if len(r.Skills) > 0 && !slices.Contains(r.Skills, request.Skill) {
+		return false
+	}
+	if !request.User.IsAuthenticated() {
+		return r.AllowAnonymous
+	}
+	return len(r.Users) == 0 || slices.Contains(r.Users, request.User.UserName())
+}
+
+// RuleSet is a declarative Authorizer: an operation is allowed if one of the rules matches it.
+type RuleSet []Rule
+
+func (rs RuleSet) Authorize(ctx context.Context, request AuthorizationRequest) error {
+	for _, rule := range rs {
+		if rule.matches(request) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no rule allows %s to call %s", describeUser(request.User), request.Method)
+}
+
+// authorize checks that the user of the call may perform the operation on the task.
+// Operations on a task are reserved to its owner, and then submitted to the authorizer.
+func (d *DefaultHandler) authorize(ctx *server.CallContext, method string, task *types.Task, metadata ...map[string]any) error {
+	request := AuthorizationRequest{
+		User:   auth.AnonymousUser{},
+		Method: method,
+		Skill:  skillOf(metadata...),
+	}
+	if user := ctx.GetUser(); user != nil {
+		request.User = user
+	}
+	if task != nil {
+		request.TaskId = task.Id
+		request.TaskOwner = manager.TaskOwner(task)
+		if request.Skill == "" && len(task.History) > 0 && task.History[0] != nil {
+			request.Skill = skillOf(task.History[0].Metadata)
+		}
+	}
+
+	if request.TaskOwner != "" && request.TaskOwner != request.User.UserName() {
+		return fmt.Errorf("%w: task %s belongs to another user", ErrPermissionDenied, request.TaskId)
+	}
+	if d.authorizer == nil {
+		return nil
+	}
+	if err := d.authorizer.Authorize(ctx, request); err != nil {
+		if errors.Is(err, ErrPermissionDenied) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrPermissionDenied, err)
+	}
+	return nil
+}
+
+// owner returns the name the tasks created by the user of the call are recorded with.
+func owner(ctx *server.CallContext) string {
+	user := ctx.GetUser()
+	if user == nil || !user.IsAuthenticated() {
+		return ""
+	}
+	return user.UserName()
+}
+
+// skillOf returns the skill named by the first metadata that has one.
+func skillOf(metadata ...map[string]any) string {
+	for _, m := range metadata {
+		if skill, ok := m[scheduler.MetadataSkill].(string); ok && skill != "" {
+			return skill
+		}
+	}
+	return ""
+}
+
+func describeUser(user auth.User) string {
+	if !user.IsAuthenticated() {
+		return "anonymous user"
+	}
+	return fmt.Sprintf("user %q", user.UserName())
+}
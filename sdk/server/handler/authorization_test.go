@@ -0,0 +1,241 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+	"github.com/yeeaiclub/a2a-go/sdk/server"
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/server/scheduler"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/manager"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestRuleSet(t *testing.T) {
+	rules := RuleSet{
+		{Methods: []string{types.MethodMessageSend}, Skills: []string{"search"}, AllowAnonymous: true},
+		{Skills: []string{"admin"}, Users: []string{"alice"}},
+		{Methods: []string{types.MethodTasksGet, types.MethodTasksCancel}},
+	}
+
+	testcases := []struct {
+		name    string
+		request AuthorizationRequest
+		wantErr bool
+	}{
+		{
+			name:    "anonymous user on public skill",
+			request: AuthorizationRequest{User: auth.AnonymousUser{}, Method: types.MethodMessageSend, Skill: "search"},
+		},
+		{
+			name:    "anonymous user on other method",
+			request: AuthorizationRequest{User: auth.AnonymousUser{}, Method: types.MethodTasksGet},
+			wantErr: true,
+		},
+		{
+			name:    "listed user on restricted skill",
+			request: AuthorizationRequest{User: auth.BasicUser{Name: "alice"}, Method: types.MethodMessageSend, Skill: "admin"},
+		},
+		{
+			name:    "other user on restricted skill",
+			request: AuthorizationRequest{User: auth.BasicUser{Name: "bob"}, Method: types.MethodMessageSend, Skill: "admin"},
+			wantErr: true,
+		},
+		{
+			name:    "authenticated user on task method",
+			request: AuthorizationRequest{User: auth.BasicUser{Name: "bob"}, Method: types.MethodTasksCancel},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := rules.Authorize(context.Background(), tc.request)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func newUserContext(user auth.User) *server.CallContext {
+	ctx := server.NewCallContext(context.Background())
+	if user != nil {
+		ctx.SetUser(user)
+	}
+	return ctx
+}
+
+func TestTaskOwnership(t *testing.T) {
+	store := tasks.NewInMemoryTaskStore()
+	handler := NewDefaultHandler(store, newExecutor(), WithQueueManager(QueueManger{}))
+
+	ctx := newUserContext(auth.BasicUser{Name: "alice"})
+	defer ctx.Release()
+	_, err := handler.OnMessageSend(ctx, types.MessageSendParam{
+		Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User},
+	})
+	require.NoError(t, err)
+
+	task, err := store.Get(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", manager.TaskOwner(task))
+
+	testcases := []struct {
+		name    string
+		user    auth.User
+		wantErr error
+	}{
+		{
+			name: "owner",
+			user: auth.BasicUser{Name: "alice"},
+		},
+		{
+			name:    "other user",
+			user:    auth.BasicUser{Name: "bob"},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name:    "anonymous user",
+			user:    nil,
+			wantErr: ErrPermissionDenied,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := newUserContext(tc.user)
+			defer ctx.Release()
+
+			_, err := handler.OnGetTask(ctx, types.TaskQueryParams{Id: "1"})
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				_, err = handler.OnCancelTask(ctx, types.TaskIdParams{Id: "1"})
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// metadataExecutor completes tasks with the metadata of the message as their metadata.
+type metadataExecutor struct{}
+
+func (e metadataExecutor) Execute(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	u := updater.NewTaskUpdater(queue, requestContext.TaskId, requestContext.ContextId)
+	u.Complete(updater.WithMetadata(requestContext.Params.Message.Metadata))
+	return nil
+}
+
+func (e metadataExecutor) Cancel(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	return nil
+}
+
+func TestTaskOwnershipFromMetadata(t *testing.T) {
+	store := tasks.NewInMemoryTaskStore()
+	handler := NewDefaultHandler(store, metadataExecutor{}, WithQueueManager(QueueManger{}))
+
+	ctx := newUserContext(auth.BasicUser{Name: "alice"})
+	defer ctx.Release()
+	_, err := handler.OnMessageSend(ctx, types.MessageSendParam{
+		Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User, Metadata: map[string]any{"a2a.owner": "mallory"}},
+	})
+	require.NoError(t, err)
+
+	mallory := newUserContext(auth.BasicUser{Name: "mallory"})
+	defer mallory.Release()
+	_, err = handler.OnGetTask(mallory, types.TaskQueryParams{Id: "1"})
+	require.ErrorIs(t, err, ErrPermissionDenied, "client metadata does not claim the task")
+
+	task, err := handler.OnGetTask(ctx, types.TaskQueryParams{Id: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", manager.TaskOwner(task))
+	data, err := json.Marshal(task)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "alice", "the owner is not sent to clients")
+}
+
+func TestAuthorizer(t *testing.T) {
+	testcases := []struct {
+		name    string
+		user    auth.User
+		params  types.MessageSendParam
+		wantErr error
+	}{
+		{
+			name: "skill allowed",
+			user: auth.BasicUser{Name: "alice"},
+			params: types.MessageSendParam{
+				Message: &types.Message{TaskID: "1", ContextID: "2", Metadata: map[string]any{scheduler.MetadataSkill: "admin"}},
+			},
+		},
+		{
+			name: "skill denied",
+			user: auth.BasicUser{Name: "bob"},
+			params: types.MessageSendParam{
+				Message: &types.Message{TaskID: "1", ContextID: "2", Metadata: map[string]any{scheduler.MetadataSkill: "admin"}},
+			},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "skill from request metadata",
+			user: auth.BasicUser{Name: "bob"},
+			params: types.MessageSendParam{
+				Message:  &types.Message{TaskID: "1", ContextID: "2"},
+				Metadata: map[string]any{scheduler.MetadataSkill: "admin"},
+			},
+			wantErr: ErrPermissionDenied,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []AuthorizationRequest
+			authorizer := AuthorizerFunc(func(ctx context.Context, request AuthorizationRequest) error {
+				requests = append(requests, request)
+				return nil
+			})
+			rules := RuleSet{{Skills: []string{"admin"}, Users: []string{"alice"}}}
+			handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(),
+				WithQueueManager(QueueManger{}),
+				WithAuthorizer(AllOf(authorizer, rules)),
+			)
+
+			ctx := newUserContext(tc.user)
+			defer ctx.Release()
+			_, err := handler.OnMessageSend(ctx, tc.params)
+			require.Len(t, requests, 1)
+			assert.Equal(t, types.MethodMessageSend, requests[0].Method)
+			assert.Equal(t, "admin", requests[0].Skill)
+			assert.Equal(t, tc.user, requests[0].User)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
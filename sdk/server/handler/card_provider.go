@@ -0,0 +1,232 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultCardPollInterval = 2 * time.Second
+	defaultCardCacheControl = "public, max-age=60"
+)
+
+// AgentCardProvider provides the agent card served by the server. It is consulted on every
+// request, so the card can change while the server is running.
+type AgentCardProvider interface {
+	AgentCard(ctx context.Context) (types.AgentCard, error)
+}
+
+// AgentCardProviderFunc is a function type for AgentCardProvider.
+type AgentCardProviderFunc func(ctx context.Context) (types.AgentCard, error)
+
+func (fn AgentCardProviderFunc) AgentCard(ctx context.Context) (types.AgentCard, error) {
+	return fn(ctx)
+}
+
+// StaticAgentCard returns a provider that always provides the same card.
+func StaticAgentCard(card types.AgentCard) AgentCardProvider {
+	return AgentCardProviderFunc(func(ctx context.Context) (types.AgentCard, error) {
+		return card, nil
+	})
+}
+
+// FileAgentCardProvider provides the agent card read from a JSON or YAML file. The file
+// is polled for changes and reloaded; a file that fails to load or holds a card with
+// validation errors keeps the previous card.
+type FileAgentCardProvider struct {
+	path     string
+	interval time.Duration
+
+	card    atomic.Pointer[types.AgentCard]
+	mu      sync.Mutex // Serializes reloads
+	modTime time.Time  // Modification time of the loaded file
+	size    int64      // Size of the loaded file
+
+	stop chan struct{}
+	once sync.Once
+	done chan struct{}
+}
+
+// NewFileAgentCardProvider loads the card from the file at path and starts watching it.
+// Files with a .yaml or .yml extension are read as YAML, others as JSON. Close stops watching.
+func NewFileAgentCardProvider(path string, opts ...FileAgentCardProviderOption) (*FileAgentCardProvider, error) {
+	p := &FileAgentCardProvider{
+		path:     path,
+		interval: defaultCardPollInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt.Option(p)
+	}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	go p.watch()
+	return p, nil
+}
+
+// AgentCard returns the card last loaded from the file.
+func (p *FileAgentCardProvider) AgentCard(ctx context.Context) (types.AgentCard, error) {
+	return *p.card.Load(), nil
+}
+
+// Reload reads the file and replaces the card if it is valid.
+func (p *FileAgentCardProvider) Reload() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reload()
+}
+
+func (p *FileAgentCardProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat agent card file: %w", err)
+	}
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read agent card file: %w", err)
+	}
+	card, err := decodeAgentCard(p.path, data)
+	if err != nil {
+		return fmt.Errorf("failed to decode agent card file %s: %w", p.path, err)
+	}
+	if err := card.Validate().Err(); err != nil {
+		return fmt.Errorf("agent card file %s: %w", p.path, err)
+	}
+	p.card.Store(&card)
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+	return nil
+}
+
+// Close stops watching the file.
+func (p *FileAgentCardProvider) Close() error {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+	return nil
+}
+
+func (p *FileAgentCardProvider) watch() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.reloadIfChanged()
+		}
+	}
+}
+
+// reloadIfChanged reloads the file when its modification time or size changed.
+func (p *FileAgentCardProvider) reloadIfChanged() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	info, err := os.Stat(p.path)
+	if err != nil {
+		log.Warnf("failed to stat agent card file %s: %v", p.path, err)
+		return
+	}
+	if info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return
+	}
+	if err := p.reload(); err != nil {
+		log.Errorf("keeping the current agent card: %v", err)
+		return
+	}
+	log.Infof("reloaded agent card from %s", p.path)
+}
+
+// cardETag returns the strong entity tag of an encoded card.
+func cardETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether the If-None-Match header matches the entity tag,
+// using the weak comparison required for GET requests (RFC 9110, section 13.1.2).
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeAgentCard decodes a JSON or YAML card. YAML is converted to JSON first,
+// so both formats use the json field names of types.AgentCard.
+func decodeAgentCard(path string, data []byte) (types.AgentCard, error) {
+	var card types.AgentCard
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var value any
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return card, err
+		}
+		converted, err := json.Marshal(value)
+		if err != nil {
+			return card, err
+		}
+		data = converted
+	}
+	if err := json.Unmarshal(data, &card); err != nil {
+		return card, err
+	}
+	return card, nil
+}
+
+// FileAgentCardProviderOption allows customizing FileAgentCardProvider via functional options.
+type FileAgentCardProviderOption interface {
+	Option(p *FileAgentCardProvider)
+}
+
+// FileAgentCardProviderOptionFunc is a function type for FileAgentCardProviderOption.
+type FileAgentCardProviderOptionFunc func(p *FileAgentCardProvider)
+
+func (fn FileAgentCardProviderOptionFunc) Option(p *FileAgentCardProvider) {
+	fn(p)
+}
+
+// WithPollInterval sets how often the file is checked for changes.
+func WithPollInterval(interval time.Duration) FileAgentCardProviderOption {
+	return FileAgentCardProviderOptionFunc(func(p *FileAgentCardProvider) {
+		p.interval = interval
+	})
+}
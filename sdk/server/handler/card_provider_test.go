@@ -0,0 +1,195 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestFileAgentCardProvider(t *testing.T) {
+	testcases := []struct {
+		name    string
+		file    string
+		content string
+		updated string
+		want    string
+	}{
+		{
+			name:    "json",
+			file:    "agent.json",
+			content: `{"name": "agent", "version": "1.0.0", "skills": [{"id": "search"}]}`,
+			updated: `{"name": "agent", "version": "1.1.0", "skills": [{"id": "search"}, {"id": "summarize"}]}`,
+			want:    "1.1.0",
+		},
+		{
+			name:    "yaml",
+			file:    "agent.yaml",
+			content: "name: agent\nversion: 1.0.0\nskills:\n  - id: search\n",
+			updated: "name: agent\nversion: 1.1.0\nskills:\n  - id: search\n  - id: summarize\n",
+			want:    "1.1.0",
+		},
+		{
+			name:    "invalid update keeps the card",
+			file:    "agent.json",
+			content: `{"name": "agent", "version": "1.0.0", "skills": [{"id": "search"}]}`,
+			updated: `{"name": "agent", "version": `,
+			want:    "1.0.0",
+		},
+		{
+			name:    "card with errors keeps the card",
+			file:    "agent.json",
+			content: `{"name": "agent", "version": "1.0.0", "skills": [{"id": "search"}]}`,
+			updated: `{"name": "agent", "version": "1.1.0", "skills": [{"id": "search"}, {"id": "search"}]}`,
+			want:    "1.0.0",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			provider, err := NewFileAgentCardProvider(path, WithPollInterval(time.Hour))
+			require.NoError(t, err)
+			defer provider.Close()
+
+			card, err := provider.AgentCard(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, "1.0.0", card.Version)
+			assert.Equal(t, []types.AgentSkill{{ID: "search"}}, card.Skills)
+
+			require.NoError(t, os.WriteFile(path, []byte(tc.updated), 0o600))
+			require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+			provider.reloadIfChanged()
+
+			card, err = provider.AgentCard(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, card.Version)
+		})
+	}
+}
+
+func TestFileAgentCardProviderInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.json")
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0o600))
+
+	_, err := NewFileAgentCardProvider(path)
+	assert.Error(t, err)
+}
+
+func TestServerValidate(t *testing.T) {
+	invalid := mockAgentCard
+	invalid.Skills = []types.AgentSkill{{Name: "skill without id"}}
+
+	testcases := []struct {
+		name    string
+		card    types.AgentCard
+		options []ServerConfigOption
+		wantErr bool
+	}{
+		{name: "valid card", card: mockAgentCard},
+		{name: "invalid card", card: invalid, wantErr: true},
+		{name: "invalid extended card", card: mockAgentCard, options: []ServerConfigOption{WithExtendedAgentCard(invalid)}, wantErr: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+			server := NewServer("/card", "/", tc.card, handler, tc.options...)
+
+			err := server.Validate(context.Background())
+			_, handlerErr := server.Handler()
+			_, httpHandlerErr := NewHTTPHandler("/card", "/", tc.card, handler, tc.options...)
+			mountErr := NewRouter().Mount("/agent", tc.card, handler, tc.options...)
+			mountHostErr := NewRouter().MountHost("agent.example.com", tc.card, handler, tc.options...)
+			if !tc.wantErr {
+				assert.NoError(t, err)
+				assert.NoError(t, handlerErr)
+				assert.NoError(t, httpHandlerErr)
+				assert.NoError(t, mountErr)
+				assert.NoError(t, mountHostErr)
+				return
+			}
+			var validationErr *types.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Error(t, server.Start(context.Background(), 0), "the server refuses to start")
+			assert.ErrorAs(t, handlerErr, &validationErr, "the server refuses to serve the card")
+			assert.ErrorAs(t, httpHandlerErr, &validationErr)
+			assert.ErrorAs(t, mountErr, &validationErr, "the router refuses to mount the agent")
+			assert.ErrorAs(t, mountHostErr, &validationErr)
+		})
+	}
+}
+
+func TestGetCardRevalidation(t *testing.T) {
+	card := mockAgentCard
+	provider := AgentCardProviderFunc(func(ctx context.Context) (types.AgentCard, error) {
+		return card, nil
+	})
+	handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+	server := NewServer("/card", "/", types.AgentCard{}, handler, WithAgentCardProvider(provider))
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/card", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		server.handleGetAgentCard(w, req)
+		return w
+	}
+
+	w := get("")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, defaultCardCacheControl, w.Header().Get("Cache-Control"))
+	var served types.AgentCard
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&served))
+	assert.Equal(t, mockAgentCard, served)
+
+	testcases := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "matching etag", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "weak matching etag", ifNoneMatch: `"other", W/` + etag, wantStatus: http.StatusNotModified},
+		{name: "stale etag", ifNoneMatch: `"other"`, wantStatus: http.StatusOK},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := get(tc.ifNoneMatch)
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+		})
+	}
+
+	card.Skills = append(card.Skills, types.AgentSkill{ID: "new-skill"})
+	w = get(etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+)
+
+// CORSConfig is the CORS policy of the server, allowing browser-based clients of other origins.
+type CORSConfig struct {
+	// AllowedOrigins are the origins allowed to call the server. An origin is either exact,
+	// such as "https://app.example.com", or a pattern with a single wildcard, such as
+	// "https://*.example.com". "*" allows every origin. No origin is allowed if empty.
+	AllowedOrigins []string
+	// AllowedMethods are the methods allowed by preflight requests. By default, GET and POST.
+	AllowedMethods []string
+	// AllowedHeaders are the request headers allowed by preflight requests. "*" allows every
+	// requested header. By default, Content-Type and Authorization.
+	AllowedHeaders []string
+	// ExposedHeaders are the response headers readable by the client.
+	ExposedHeaders []string
+	// AllowCredentials allows requests with cookies or HTTP authentication. It only applies to
+	// the origins matching an exact origin or a pattern: the origins only allowed by "*" never
+	// get credentials, as any website could then call the server on behalf of its users.
+	AllowCredentials bool
+	// MaxAge is how long the result of a preflight request may be cached, none if zero.
+	MaxAge time.Duration
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost}
+	defaultCORSHeaders = []string{"Content-Type", "Authorization"}
+)
+
+// CORS returns a middleware applying the CORS policy. It answers the preflight requests
+// of allowed origins, and sets the CORS headers on the responses to allowed origins.
+// Requests from other origins are served without CORS headers, so browsers reject them.
+func CORS(config CORSConfig) Middleware {
+	policy := newCORSPolicy(config)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				policy.preflight(w, r, origin)
+				return
+			}
+			if policy.allowOrigin(w, origin) && len(policy.exposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(policy.exposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type corsPolicy struct {
+	origins          []string
+	anyOrigin        bool
+	methods          []string
+	headers          []string
+	anyHeader        bool
+	exposedHeaders   []string
+	allowCredentials bool
+	maxAge           string
+}
+
+func newCORSPolicy(config CORSConfig) *corsPolicy {
+	policy := &corsPolicy{
+		methods:          config.AllowedMethods,
+		exposedHeaders:   config.ExposedHeaders,
+		allowCredentials: config.AllowCredentials,
+	}
+	for _, origin := range config.AllowedOrigins {
+		if origin == "*" {
+			policy.anyOrigin = true
+		}
+		policy.origins = append(policy.origins, strings.ToLower(origin))
+	}
+	if len(policy.methods) == 0 {
+		policy.methods = defaultCORSMethods
+	}
+	headers := config.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	for _, header := range headers {
+		if header == "*" {
+			policy.anyHeader = true
+		}
+		policy.headers = append(policy.headers, http.CanonicalHeaderKey(header))
+	}
+	if config.MaxAge > 0 {
+		policy.maxAge = strconv.Itoa(int(config.MaxAge.Seconds()))
+	}
+	if policy.anyOrigin && policy.allowCredentials {
+		log.Warnf(`CORS allows every origin with "*": credentials are only allowed to the other origins`)
+	}
+	return policy
+}
+
+// preflight answers the preflight request, with the CORS headers if the request is allowed.
+func (p *corsPolicy) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+	defer w.WriteHeader(http.StatusNoContent)
+
+	method := r.Header.Get("Access-Control-Request-Method")
+	requested := requestedHeaders(r)
+	if !p.allowMethod(method) || !p.allowHeaders(requested) || !p.allowOrigin(w, origin) {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(p.methods, ", "))
+	if p.anyHeader {
+		if len(requested) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+		}
+	} else {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(p.headers, ", "))
+	}
+	if p.maxAge != "" {
+		w.Header().Set("Access-Control-Max-Age", p.maxAge)
+	}
+}
+
+// allowOrigin sets the allowed origin headers, and reports whether the origin is allowed.
+func (p *corsPolicy) allowOrigin(w http.ResponseWriter, origin string) bool {
+	listed := p.matchOrigin(strings.ToLower(origin))
+	if !listed && !p.anyOrigin {
+		return false
+	}
+	if listed && p.allowCredentials {
+		// Browsers reject the wildcard for requests with credentials.
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	} else if p.anyOrigin {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	return true
+}
+
+// matchOrigin reports whether the origin matches an exact origin or a pattern, "*" aside.
+func (p *corsPolicy) matchOrigin(origin string) bool {
+	for _, pattern := range p.origins {
+		if pattern == "*" {
+			continue
+		}
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		if !wildcard {
+			if origin == pattern {
+				return true
+			}
+			continue
+		}
+		if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *corsPolicy) allowMethod(method string) bool {
+	for _, allowed := range p.methods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *corsPolicy) allowHeaders(requested []string) bool {
+	if p.anyHeader {
+		return true
+	}
+	for _, header := range requested {
+		if !p.allowHeader(header) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *corsPolicy) allowHeader(header string) bool {
+	for _, allowed := range p.headers {
+		if allowed == header {
+			return true
+		}
+	}
+	return false
+}
+
+// requestedHeaders returns the canonical headers of the Access-Control-Request-Headers header.
+func requestedHeaders(r *http.Request) []string {
+	var headers []string
+	for _, value := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, http.CanonicalHeaderKey(header))
+			}
+		}
+	}
+	return headers
+}
+
+// WithCORS sets the CORS policy of the server, answering the preflight requests of the agent
+// card and JSON-RPC endpoints. By default, no CORS header is set.
+func WithCORS(config CORSConfig) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.cors = CORS(config)
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestCORS(t *testing.T) {
+	newHandler := func(config CORSConfig) http.Handler {
+		handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+		h, err := NewServer(types.AgentCardPath, "/", mockAgentCard, handler, WithCORS(config),
+			WithMiddleware(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodOptions {
+						t.Error("preflight requests are answered before the middlewares")
+					}
+					next.ServeHTTP(w, r)
+				})
+			})).Handler()
+		require.NoError(t, err)
+		return h
+	}
+	sendBody, err := json.Marshal(types.JSONRPCRequest{
+		Id:     "1",
+		Method: types.MethodMessageSend,
+		Params: types.MessageSendParam{Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User}},
+	})
+	assert.NoError(t, err)
+
+	testcases := []struct {
+		name        string
+		config      CORSConfig
+		method      string
+		path        string
+		origin      string
+		headers     map[string]string
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name:        "agent card for any origin",
+			config:      CORSConfig{AllowedOrigins: []string{"*"}},
+			method:      http.MethodGet,
+			path:        types.AgentCardPath,
+			origin:      "https://app.example.com",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+		{
+			name:        "unary request for an exact origin",
+			config:      CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, ExposedHeaders: []string{"ETag"}},
+			method:      http.MethodPost,
+			path:        "/",
+			origin:      "https://app.example.com",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "https://app.example.com", "Access-Control-Expose-Headers": "ETag"},
+		},
+		{
+			name:        "origin not allowed",
+			config:      CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			method:      http.MethodPost,
+			path:        "/",
+			origin:      "https://evil.example.com",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:       "preflight of the JSON-RPC endpoint",
+			config:     CORSConfig{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true, MaxAge: 10 * time.Minute},
+			method:     http.MethodOptions,
+			path:       "/",
+			origin:     "https://app.example.com",
+			headers:    map[string]string{"Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "content-type, authorization"},
+			wantStatus: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+				"Access-Control-Max-Age":           "600",
+			},
+		},
+		{
+			name:        "no credentials for any origin",
+			config:      CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method:      http.MethodPost,
+			path:        "/",
+			origin:      "https://evil.example.com",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Credentials": ""},
+		},
+		{
+			name:        "credentials for a listed origin besides any origin",
+			config:      CORSConfig{AllowedOrigins: []string{"*", "https://app.example.com"}, AllowCredentials: true},
+			method:      http.MethodPost,
+			path:        "/",
+			origin:      "https://app.example.com",
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "https://app.example.com", "Access-Control-Allow-Credentials": "true"},
+		},
+		{
+			name:        "preflight of the agent card",
+			config:      CORSConfig{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}},
+			method:      http.MethodOptions,
+			path:        types.AgentCardPath,
+			origin:      "https://app.example.com",
+			headers:     map[string]string{"Access-Control-Request-Method": "GET", "Access-Control-Request-Headers": "X-API-Key"},
+			wantStatus:  http.StatusNoContent,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Headers": "X-Api-Key"},
+		},
+		{
+			name:        "wildcard requires a subdomain",
+			config:      CORSConfig{AllowedOrigins: []string{"https://*.example.com"}},
+			method:      http.MethodOptions,
+			path:        "/",
+			origin:      "https://.example.com",
+			headers:     map[string]string{"Access-Control-Request-Method": "POST"},
+			wantStatus:  http.StatusNoContent,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:        "preflight of a method not allowed",
+			config:      CORSConfig{AllowedOrigins: []string{"*"}},
+			method:      http.MethodOptions,
+			path:        "/",
+			origin:      "https://app.example.com",
+			headers:     map[string]string{"Access-Control-Request-Method": "DELETE"},
+			wantStatus:  http.StatusNoContent,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+		},
+		{
+			name:        "preflight of a header not allowed",
+			config:      CORSConfig{AllowedOrigins: []string{"*"}},
+			method:      http.MethodOptions,
+			path:        "/",
+			origin:      "https://app.example.com",
+			headers:     map[string]string{"Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "X-Custom"},
+			wantStatus:  http.StatusNoContent,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body *bytes.Reader
+			if tc.method == http.MethodPost {
+				body = bytes.NewReader(sendBody)
+			} else {
+				body = bytes.NewReader(nil)
+			}
+			req := httptest.NewRequest(tc.method, tc.path, body)
+			req.Header.Set("Origin", tc.origin)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			newHandler(tc.config).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatus, w.Code)
+			for key, value := range tc.wantHeaders {
+				assert.Equal(t, value, w.Header().Get(key), key)
+			}
+			assert.Contains(t, w.Header().Values("Vary"), "Origin")
+		})
+	}
+}
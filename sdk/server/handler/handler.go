@@ -17,14 +17,19 @@ package handler
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/yeeaiclub/a2a-go/internal/errs"
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
 	"github.com/yeeaiclub/a2a-go/sdk/server"
 	"github.com/yeeaiclub/a2a-go/sdk/server/event"
 	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/server/scheduler"
 	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
 	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/aggregator"
 	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/manager"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
 	"github.com/yeeaiclub/a2a-go/sdk/types"
 )
 
@@ -54,11 +59,22 @@ type DefaultHandler struct {
 	executor         execution.AgentExecutor      // Agent execution engine
 	resultAggregator *aggregator.ResultAggregator // Aggregates results from event queue
 	pushNotifier     tasks.PushNotifier           // Push notification handler
+	authorizer       Authorizer                   // Authorizes operations, owner checks only if nil
+	shutdownState    types.TaskState              // State of the tasks interrupted by Shutdown
+	scheduler        scheduler.Scheduler          // Runs the executors, each in its own goroutine if nil
+	panicHandler     PanicHandler                 // Reports the recovered panics of the executors
+	executionTimeout time.Duration                // Deadline of the executors, none if zero
+	skillTimeouts    map[string]time.Duration     // Deadlines of the executors by skill
+
+	mu      sync.Mutex
+	closed  bool                           // Set by Shutdown, no new execution is started
+	running map[*runningExecution]struct{} // Executors running in the background
+	wg      sync.WaitGroup                 // Waits for the running executors
 }
 
 // NewDefaultHandler creates a new DefaultHandler with optional configuration.
 func NewDefaultHandler(store tasks.TaskStore, executor execution.AgentExecutor, opts ...HandlerOption) *DefaultHandler {
-	handler := &DefaultHandler{store: store, executor: executor}
+	handler := &DefaultHandler{store: store, executor: executor, shutdownState: types.FAILED}
 	for _, opt := range opts {
 		opt.Option(handler)
 	}
@@ -75,25 +91,38 @@ func (d *DefaultHandler) OnGetTask(ctx *server.CallContext, params types.TaskQue
 	if task == nil {
 		return nil, errs.ErrTaskNotFound
 	}
+	if err := d.authorize(ctx, types.MethodTasksGet, task, params.Metadata); err != nil {
+		return nil, err
+	}
 	return task, nil
 }
 
-// OnMessageSend handles synchronous message send requests and waits for the result.
+// OnMessageSend handles message send requests. It waits for the result, unless the configuration
+// of the request sets Blocking to false, in which case it returns the task right away, in its
+// submitted or working state, while the task runs in the background. The client can then poll
+// the task with tasks/get or rely on push notifications.
 func (d *DefaultHandler) OnMessageSend(ctx *server.CallContext, params types.MessageSendParam) (types.Event, error) {
 	if params.Message == nil {
 		return nil, errs.ErrNilMessage
 	}
+	if d.isClosed() {
+		return nil, ErrShuttingDown
+	}
 	taskManager := manager.NewTaskManager(
 		d.store,
 		manager.WithTaskId(params.Message.TaskID),
 		manager.WithContextId(params.Message.ContextID),
 		manager.WithInitMessage(params.Message),
+		manager.WithOwner(owner(ctx)),
 	)
 
 	task, err := taskManager.GetTask(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err := d.authorize(ctx, types.MethodMessageSend, task, params.Message.Metadata, params.Metadata); err != nil {
+		return nil, err
+	}
 
 	if task != nil {
 		if d.IsTerminalTaskSates(task.Status.State) {
@@ -124,10 +153,12 @@ func (d *DefaultHandler) OnMessageSend(ctx *server.CallContext, params types.Mes
 		return nil, err
 	}
 
+	if !isBlocking(params) {
+		return d.sendInBackground(ctx, taskManager, reqContext, queue, task == nil)
+	}
+
 	d.execute(ctx, reqContext, queue)
-	ev, err := aggregator.NewResultAggregator(taskManager).
-		BuildInterruptible().
-		Consume(ctx, queue)
+	ev, err := awaitResult(ctx, reqContext.TaskId, taskManager, queue)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +169,66 @@ func (d *DefaultHandler) OnMessageSend(ctx *server.CallContext, params types.Mes
 	return ev, nil
 }
 
+// isBlocking reports whether the request waits for the result of the task.
+func isBlocking(params types.MessageSendParam) bool {
+	return params.Configuration == nil || params.Configuration.Blocking == nil || *params.Configuration.Blocking
+}
+
+// awaitResult waits for the result of the task, or until the request is done. The events are
+// consumed in the background, under a context outliving the request, so that the task is still
+// kept up to date once the client is gone.
+func awaitResult(ctx context.Context, taskId string, taskManager *manager.TaskManager, queue *event.Queue) (types.Event, error) {
+	type result struct {
+		event types.Event
+		err   error
+	}
+	done := make(chan result, 1)
+	background := context.WithoutCancel(ctx)
+	go func() {
+		ev, err := aggregator.NewResultAggregator(taskManager).BuildInterruptible().Consume(background, queue)
+		if err != nil && ctx.Err() != nil {
+			log.Errorf("task %s running in the background: %v", taskId, err)
+		}
+		done <- result{event: ev, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.event, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// sendInBackground starts the task and returns it right away, while its events are consumed
+// in the background, under a context outliving the request, to keep the task up to date.
+func (d *DefaultHandler) sendInBackground(ctx *server.CallContext, taskManager *manager.TaskManager,
+	reqContext *execution.RequestContext, queue *event.Queue, newTask bool) (types.Event, error) {
+	task, err := taskManager.EnsureTask(ctx, reqContext.Params.Message)
+	if err != nil {
+		return nil, err
+	}
+	if newTask && d.shouldAddPushInfo(reqContext.Params) {
+		if err := d.pushNotifier.SetInfo(ctx, task.Id, reqContext.Params.Configuration.PushNotificationConfig); err != nil {
+			return nil, err
+		}
+	}
+	// The consumer updates the task of the manager, the caller gets a copy.
+	result := tasks.CloneTask(task)
+
+	d.execute(ctx, reqContext, queue)
+	background := context.WithoutCancel(ctx)
+	go func() {
+		if _, err := aggregator.NewResultAggregator(taskManager).BuildFull().Consume(background, queue); err != nil {
+			log.Errorf("task %s running in the background: %v", task.Id, err)
+			// No one consumes the queue anymore, the task is only failed in the store.
+			u := updater.NewTaskUpdater(nil, task.Id, task.ContextId)
+			d.markFailed(background, reqContext, failedStatus(u, fmt.Sprintf("The task failed: %v", err)))
+		}
+	}()
+	return result, nil
+}
+
 // OnMessageSendStream handles streaming message send requests, returning a channel of events.
 func (d *DefaultHandler) OnMessageSendStream(ctx *server.CallContext, params types.MessageSendParam) <-chan types.StreamEvent {
 	errorStream := func(err error) <-chan types.StreamEvent {
@@ -150,18 +241,25 @@ func (d *DefaultHandler) OnMessageSendStream(ctx *server.CallContext, params typ
 	if params.Message == nil {
 		return errorStream(errs.ErrNilMessage)
 	}
+	if d.isClosed() {
+		return errorStream(ErrShuttingDown)
+	}
 
 	taskManager := manager.NewTaskManager(
 		d.store,
 		manager.WithTaskId(params.Message.TaskID),
 		manager.WithContextId(params.Message.ContextID),
 		manager.WithInitMessage(params.Message),
+		manager.WithOwner(owner(ctx)),
 	)
 
 	task, err := taskManager.GetTask(ctx)
 	if err != nil {
 		return errorStream(err)
 	}
+	if err := d.authorize(ctx, types.MethodMessageStream, task, params.Message.Metadata, params.Metadata); err != nil {
+		return errorStream(err)
+	}
 
 	reqContext, err := execution.NewRequestContext(
 		execution.WithParams(params),
@@ -180,8 +278,38 @@ func (d *DefaultHandler) OnMessageSendStream(ctx *server.CallContext, params typ
 	}
 
 	d.execute(ctx, reqContext, queue)
+	// The consumer outlives the request to keep the task up to date, the request only taps its events.
+	events := aggregator.NewResultAggregator(taskManager).BuildStreaming().Consume(context.WithoutCancel(ctx), queue)
+	return relay(ctx, events)
+}
 
-	return aggregator.NewResultAggregator(taskManager).BuildStreaming().Consume(ctx, queue)
+// relay forwards the events to the request until it is done. The rest of the events are then
+// drained, so that their consumer is not blocked and still processes them.
+func relay(ctx context.Context, events <-chan types.StreamEvent) <-chan types.StreamEvent {
+	out := make(chan types.StreamEvent)
+	go func() {
+		defer func() {
+			for range events {
+			}
+		}()
+		defer close(out)
+		for {
+			select {
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
 }
 
 // OnCancelTask handles task cancellation requests.
@@ -198,6 +326,9 @@ func (d *DefaultHandler) OnCancelTask(ctx *server.CallContext, params types.Task
 	if task.Id == "" {
 		return nil, errs.ErrTaskNotFound
 	}
+	if err := d.authorize(ctx, types.MethodTasksCancel, task, params.Metadata); err != nil {
+		return nil, err
+	}
 
 	taskManager := manager.NewTaskManager(
 		d.store,
@@ -250,6 +381,9 @@ func (d *DefaultHandler) OnSetTaskPushNotificationConfig(ctx *server.CallContext
 	if task == nil {
 		return nil, errs.ErrTaskNotFound
 	}
+	if err := d.authorize(ctx, types.MethodPushNotificationSet, task); err != nil {
+		return nil, err
+	}
 
 	err = d.pushNotifier.SetInfo(ctx, params.TaskId, params.Config)
 	if err != nil {
@@ -271,6 +405,9 @@ func (d *DefaultHandler) OnGetTaskPushNotificationConfig(ctx *server.CallContext
 	if task == nil {
 		return nil, errs.ErrTaskNotFound
 	}
+	if err := d.authorize(ctx, types.MethodPushNotificationGet, task, params.Metadata); err != nil {
+		return nil, err
+	}
 
 	config, err := d.pushNotifier.GetInfo(ctx, params.Id)
 	if err != nil {
@@ -280,6 +417,7 @@ func (d *DefaultHandler) OnGetTaskPushNotificationConfig(ctx *server.CallContext
 }
 
 // OnResubscribeToTask handles resubscription to task events, returning a channel of events.
+// The events are only tapped: the consumer of the running execution keeps the task up to date.
 func (d *DefaultHandler) OnResubscribeToTask(ctx *server.CallContext, params types.TaskIdParams) <-chan types.StreamEvent {
 	errorStream := func(err error) <-chan types.StreamEvent {
 		ch := make(chan types.StreamEvent, 1)
@@ -295,35 +433,74 @@ func (d *DefaultHandler) OnResubscribeToTask(ctx *server.CallContext, params typ
 	if task == nil {
 		return errorStream(errs.ErrTaskNotFound)
 	}
+	if err := d.authorize(ctx, types.MethodTasksResubscribe, task, params.Metadata); err != nil {
+		return errorStream(err)
+	}
 
-	taskManager := manager.NewTaskManager(
-		d.store,
-		manager.WithTaskId(task.Id),
-		manager.WithContextId(task.ContextId),
-	)
 	queue, err := d.queueManger.CreateOrTap(ctx, task.Id)
 	if err != nil {
 		return errorStream(err)
 	}
-	return aggregator.NewResultAggregator(taskManager).
-		BuildStreaming().
-		Consume(ctx, queue)
+	return queue.Subscribe(ctx)
 }
 
-// execute runs the agent executor in a goroutine and closes the queue on completion.
+// execute runs the agent executor in a goroutine, or on the scheduler if any, and closes the
+// queue on completion. The executor keeps running once the request is served, until it returns,
+// its deadline passes or the handler shuts down. Once the handler shuts down, ErrShuttingDown
+// is enqueued instead.
 func (d *DefaultHandler) execute(ctx context.Context, reqCtx *execution.RequestContext, queue *event.Queue) {
-	go func() {
+	// The executor outlives the request, it is only canceled by its deadline or by Shutdown.
+	ctx, run, err := d.track(context.WithoutCancel(ctx), reqCtx.TaskId)
+	if err != nil {
+		go func() {
+			defer queue.Close()
+			queue.EnqueueError(err)
+		}()
+		return
+	}
+	release := claimTaskSlot(ctx)
+	job := scheduler.NewJob(reqCtx.TaskId, reqCtx.ContextId, reqCtx.Params, queue, nil)
+	timeout := d.timeout(reqCtx.Params, job.Skill)
+	job.Run = func(ctx context.Context) {
+		defer release()
+		defer d.untrack(run)
 		defer queue.Close()
+		defer d.recoverExecutor(ctx, reqCtx, queue)
+		if err := ctx.Err(); err != nil {
+			// Canceled while waiting for the scheduler
+			queue.EnqueueError(err)
+			return
+		}
+		if timeout > 0 {
+			var stop func()
+			ctx, stop = d.withDeadline(ctx, reqCtx, queue, timeout)
+			defer stop()
+		}
 		err := d.executor.Execute(ctx, reqCtx, queue)
 		if err != nil {
 			queue.EnqueueError(err)
 		}
-	}()
+	}
+
+	if d.scheduler == nil {
+		go job.Run(ctx)
+		return
+	}
+	err = d.scheduler.Schedule(ctx, job)
+	if err != nil {
+		go func() {
+			defer release()
+			defer d.untrack(run)
+			defer queue.Close()
+			queue.EnqueueError(err)
+		}()
+	}
 }
 
 // cancel requests cancellation of a running task.
 func (d *DefaultHandler) cancel(ctx context.Context, reqCtx *execution.RequestContext, queue *event.Queue) {
 	go func() {
+		defer d.recoverCancel(ctx, reqCtx, queue)
 		err := d.executor.Cancel(ctx, reqCtx, queue)
 		if err != nil {
 			queue.EnqueueError(err)
@@ -16,7 +16,9 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +26,7 @@ import (
 	"github.com/yeeaiclub/a2a-go/sdk/server"
 	"github.com/yeeaiclub/a2a-go/sdk/server/event"
 	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/server/scheduler"
 	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
 	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
 	"github.com/yeeaiclub/a2a-go/sdk/types"
@@ -267,3 +270,223 @@ func TestOnCancelTask(t *testing.T) {
 		})
 	}
 }
+
+// gatedExecutor starts working on every task, and completes them once released.
+type gatedExecutor struct {
+	release chan struct{}
+}
+
+func (e *gatedExecutor) Execute(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	u := updater.NewTaskUpdater(queue, requestContext.TaskId, requestContext.ContextId)
+	u.StartWork()
+	select {
+	case <-e.release:
+		u.Complete()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *gatedExecutor) Cancel(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	return nil
+}
+
+func TestOnMessageSendScheduled(t *testing.T) {
+	executor := &gatedExecutor{release: make(chan struct{})}
+	store := tasks.NewInMemoryTaskStore()
+	handler := NewDefaultHandler(store, executor, WithQueueManager(QueueManger{}), WithScheduler(scheduler.NewPool(1)))
+	send := func(taskId string) <-chan types.StreamEvent {
+		return handler.OnMessageSendStream(server.NewCallContext(context.Background()), types.MessageSendParam{
+			Message: &types.Message{TaskID: taskId, ContextID: "ctx", Role: types.User},
+		})
+	}
+	state := func(ev types.StreamEvent) types.TaskState {
+		update, ok := ev.Event.(*types.TaskStatusUpdateEvent)
+		require.True(t, ok, "status update expected, got %#v", ev)
+		return update.Status.State
+	}
+
+	first := send("1")
+	assert.Equal(t, types.WORKING, state(<-first))
+	second := send("2")
+	assert.Equal(t, types.SUBMITTED, state(<-second), "waits for the worker")
+	require.Eventually(t, func() bool {
+		task, err := store.Get(context.Background(), "2")
+		return err == nil && task != nil && task.Status.State == types.SUBMITTED
+	}, time.Second, 10*time.Millisecond)
+
+	close(executor.release)
+	var states []types.TaskState
+	for ev := range second {
+		if ev.Type == types.EventData || ev.Type == types.EventDone {
+			states = append(states, state(ev))
+		}
+	}
+	assert.Equal(t, []types.TaskState{types.WORKING, types.COMPLETED}, states)
+	for range first {
+	}
+}
+
+func TestOnMessageSendNonBlocking(t *testing.T) {
+	executor := &gatedExecutor{release: make(chan struct{})}
+	store := tasks.NewInMemoryTaskStore()
+	handler := NewDefaultHandler(store, executor, WithQueueManager(QueueManger{}))
+	blocking := false
+	message := &types.Message{TaskID: "1", ContextID: "2", Role: types.User}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ev, err := handler.OnMessageSend(server.NewCallContext(ctx), types.MessageSendParam{
+		Configuration: &types.MessageSendConfiguration{Blocking: &blocking},
+		Message:       message,
+	})
+	cancel()
+	require.NoError(t, err)
+	task, ok := ev.(*types.Task)
+	require.True(t, ok, "task expected, got %#v", ev)
+	assert.Equal(t, "1", task.Id)
+	assert.Equal(t, types.SUBMITTED, task.Status.State)
+	assert.Equal(t, []*types.Message{message}, task.History)
+
+	require.Eventually(t, func() bool {
+		task, err := store.Get(context.Background(), "1")
+		return err == nil && task != nil && task.Status.State == types.WORKING
+	}, time.Second, 10*time.Millisecond, "keeps running once the request is canceled")
+	close(executor.release)
+	require.Eventually(t, func() bool {
+		task, err := store.Get(context.Background(), "1")
+		return err == nil && task != nil && task.Status.State == types.COMPLETED
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, handler.Shutdown(context.Background()))
+}
+
+type failingExecutor struct{}
+
+func (e failingExecutor) Execute(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	return errors.New("model unavailable")
+}
+
+func (e failingExecutor) Cancel(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	return nil
+}
+
+func TestOnMessageSendNonBlockingFails(t *testing.T) {
+	blocking := false
+	testcases := []struct {
+		name     string
+		executor execution.AgentExecutor
+		options  []HandlerOption
+		before   []string // Tasks sent first, running or waiting in the scheduler
+		want     string
+	}{
+		{
+			name:     "executor error",
+			executor: failingExecutor{},
+			want:     "The task failed: model unavailable",
+		},
+		{
+			name:     "scheduler queue full",
+			executor: &gatedExecutor{release: make(chan struct{})},
+			options:  []HandlerOption{WithScheduler(scheduler.NewPool(1, scheduler.WithMaxQueue(1)))},
+			before:   []string{"running", "waiting"},
+			want:     "The task failed: " + scheduler.ErrQueueFull.Error(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := tasks.NewInMemoryTaskStore()
+			handler := NewDefaultHandler(store, tc.executor, append(tc.options, WithQueueManager(QueueManger{}))...)
+			send := func(taskId string) {
+				_, err := handler.OnMessageSend(server.NewCallContext(context.Background()), types.MessageSendParam{
+					Configuration: &types.MessageSendConfiguration{Blocking: &blocking},
+					Message:       &types.Message{TaskID: taskId, ContextID: "ctx", Role: types.User},
+				})
+				require.NoError(t, err)
+			}
+			stateOf := func(taskId string) types.TaskState {
+				task, err := store.Get(context.Background(), taskId)
+				if err != nil || task == nil {
+					return ""
+				}
+				return task.Status.State
+			}
+			for _, taskId := range tc.before {
+				send(taskId)
+			}
+			if len(tc.before) > 0 {
+				require.Eventually(t, func() bool { return stateOf(tc.before[0]) == types.WORKING }, time.Second, 10*time.Millisecond)
+			}
+
+			send("1")
+			require.Eventually(t, func() bool { return stateOf("1") == types.FAILED }, time.Second, 10*time.Millisecond,
+				"the task does not stay submitted")
+			task, err := store.Get(context.Background(), "1")
+			require.NoError(t, err)
+			require.NotNil(t, task.Status.Message)
+			require.Len(t, task.Status.Message.Parts, 1)
+			assert.Equal(t, tc.want, task.Status.Message.Parts[0].(*types.TextPart).Text)
+
+			if executor, ok := tc.executor.(*gatedExecutor); ok {
+				close(executor.release)
+			}
+			assert.NoError(t, handler.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestCanceledRequestPersistsTask(t *testing.T) {
+	testcases := []struct {
+		name string
+		send func(handler *DefaultHandler, ctx *server.CallContext, params types.MessageSendParam) error
+	}{
+		{
+			name: "blocking send",
+			send: func(handler *DefaultHandler, ctx *server.CallContext, params types.MessageSendParam) error {
+				_, err := handler.OnMessageSend(ctx, params)
+				return err
+			},
+		},
+		{
+			name: "stream",
+			send: func(handler *DefaultHandler, ctx *server.CallContext, params types.MessageSendParam) error {
+				for ev := range handler.OnMessageSendStream(ctx, params) {
+					if ev.Type == types.EventError || ev.Type == types.EventCanceled {
+						return ev.Err
+					}
+				}
+				return ctx.Err()
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			executor := &gatedExecutor{release: make(chan struct{})}
+			store := tasks.NewInMemoryTaskStore()
+			handler := NewDefaultHandler(store, executor, WithQueueManager(QueueManger{}))
+			stateOf := func(state types.TaskState) func() bool {
+				return func() bool {
+					task, err := store.Get(context.Background(), "1")
+					return err == nil && task != nil && task.Status.State == state
+				}
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			sent := make(chan error, 1)
+			go func() {
+				sent <- tc.send(handler, server.NewCallContext(ctx), types.MessageSendParam{
+					Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User},
+				})
+			}()
+			require.Eventually(t, stateOf(types.WORKING), time.Second, 10*time.Millisecond)
+			cancel()
+			assert.ErrorIs(t, <-sent, context.Canceled)
+
+			close(executor.release)
+			require.Eventually(t, stateOf(types.COMPLETED), time.Second, 10*time.Millisecond,
+				"the events are saved once the client is gone")
+			assert.NoError(t, handler.Shutdown(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/yeeaiclub/a2a-go/internal/errs"
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// ErrShuttingDown is returned for requests starting a task while the server shuts down.
+var ErrShuttingDown = errs.ErrShuttingDown
+
+// Shutdowner is implemented by handlers that run agent executors in the background,
+// so that the server can drain them when it shuts down.
+type Shutdowner interface {
+	// Shutdown stops starting new tasks and waits for the running executors until the
+	// context is done, then cancels the contexts of those still running.
+	Shutdown(ctx context.Context) error
+}
+
+// Shutdown gracefully shuts the server down. It stops accepting connections and new tasks,
+// waits for the running executors until the context is done, then cancels them and sends
+// a final error frame to the open streams. It returns the error of the context if the
+// server was not drained in time, in which case the remaining connections are closed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.draining.Store(true)
+	s.mu.Lock()
+	svc := s.httpServer
+	s.mu.Unlock()
+
+	served := make(chan error, 1)
+	if svc != nil {
+		// Closes the listeners right away, and returns once the open requests are done.
+		go func() {
+			served <- svc.Shutdown(ctx)
+		}()
+	} else {
+		served <- nil
+	}
+
+	var err error
+	if h, ok := s.handler.(Shutdowner); ok {
+		err = h.Shutdown(ctx)
+	}
+	s.closeOnce.Do(func() {
+		close(s.closing)
+	})
+
+	if serveErr := <-served; serveErr != nil {
+		if svc != nil {
+			_ = svc.Close()
+		}
+		err = errors.Join(err, serveErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to shut down gracefully: %w", err)
+	}
+	return nil
+}
+
+// shuttingDown reports whether requests starting a task are rejected.
+func (s *Server) shuttingDown() bool {
+	return s.draining.Load()
+}
+
+// interruptedByShutdown reports whether a stream ends because Shutdown canceled its executor,
+// in which case the stream ends with the same final frame as when the server closes it.
+func (s *Server) interruptedByShutdown(ev types.StreamEvent) bool {
+	if !s.shuttingDown() {
+		return false
+	}
+	return ev.Type == types.EventClosed || (ev.Type == types.EventError && errors.Is(ev.Err, context.Canceled))
+}
+
+// isClosed reports whether the handler shuts down.
+func (d *DefaultHandler) isClosed() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closed
+}
+
+// runningExecution is an agent executor running in the background.
+type runningExecution struct {
+	taskId string
+	cancel context.CancelFunc
+}
+
+// track registers an execution of the task, returning the context to run it with,
+// or ErrShuttingDown once the handler shuts down.
+func (d *DefaultHandler) track(ctx context.Context, taskId string) (context.Context, *runningExecution, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil, nil, ErrShuttingDown
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	run := &runningExecution{taskId: taskId, cancel: cancel}
+	if d.running == nil {
+		d.running = make(map[*runningExecution]struct{})
+	}
+	d.running[run] = struct{}{}
+	d.wg.Add(1)
+	return ctx, run, nil
+}
+
+func (d *DefaultHandler) untrack(run *runningExecution) {
+	d.mu.Lock()
+	delete(d.running, run)
+	d.mu.Unlock()
+	run.cancel()
+	d.wg.Done()
+}
+
+// Shutdown stops starting new tasks and waits for the running executors until the context
+// is done. The executors still running are then canceled, and their tasks are marked with
+// the state set by WithShutdownTaskState, FAILED by default, unless they already reached a
+// terminal state. It returns the error of the context if some executors were canceled.
+func (d *DefaultHandler) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	d.mu.Lock()
+	interrupted := make([]*runningExecution, 0, len(d.running))
+	for run := range d.running {
+		interrupted = append(interrupted, run)
+	}
+	d.mu.Unlock()
+
+	storeCtx := context.WithoutCancel(ctx)
+	for _, run := range interrupted {
+		run.cancel()
+		if err := d.markInterrupted(storeCtx, run.taskId); err != nil {
+			log.Errorf("failed to mark task %s interrupted by shutdown: %v", run.taskId, err)
+		}
+	}
+	log.Warnf("canceled %d executors still running at shutdown", len(interrupted))
+	return ctx.Err()
+}
+
+// markInterrupted sets the shutdown state on the task unless it is terminal.
+func (d *DefaultHandler) markInterrupted(ctx context.Context, taskId string) error {
+	return d.markTask(ctx, taskId, types.TaskStatus{
+		State:     d.shutdownState,
+		TimeStamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// markTask sets the status on the task unless it is terminal.
+func (d *DefaultHandler) markTask(ctx context.Context, taskId string, status types.TaskStatus) error {
+	task, err := d.store.Get(ctx, taskId)
+	if err != nil {
+		return err
+	}
+	if task == nil || d.IsTerminalTaskSates(task.Status.State) {
+		return nil
+	}
+	updated := *task // The store may share the task with running requests
+	updated.Status = status
+	return d.store.Save(ctx, &updated)
+}
+
+// WithShutdownTimeout sets how long the server waits for running executors once the context
+// given to Start is done, before canceling them. By default, it is 30 seconds.
+func WithShutdownTimeout(timeout time.Duration) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.shutdownTimeout = timeout
+	})
+}
+
+// WithShutdownTaskState sets the state of the tasks whose executor is canceled by Shutdown.
+// By default, it is types.FAILED.
+func WithShutdownTaskState(state types.TaskState) HandlerOption {
+	return HandlerOptionFunc(func(d *DefaultHandler) {
+		d.shutdownState = state
+	})
+}
+
+// serveUntilDone serves on the server until the context is done, then shuts it down.
+func (s *Server) serveUntilDone(ctx context.Context, svc *http.Server, serve func() error) error {
+	s.mu.Lock()
+	s.httpServer = svc
+	s.mu.Unlock()
+	return serveUntilDone(ctx, s.shutdownTimeout, s.Shutdown, serve)
+}
+
+// serveUntilDone calls serve until the context is done, then calls shutdown with the timeout.
+func serveUntilDone(ctx context.Context, timeout time.Duration, shutdown func(ctx context.Context) error, serve func() error) error {
+	stopped := make(chan error, 1)
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+		stopped <- shutdown(shutdownCtx)
+	})
+
+	err := serve()
+	if !errors.Is(err, http.ErrServerClosed) {
+		stop()
+		return err
+	}
+	if stop() {
+		// Shut down by a direct call to Shutdown rather than by the context.
+		return nil
+	}
+	return <-stopped
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server"
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// blockingExecutor starts working on the task, then completes it once released.
+type blockingExecutor struct {
+	started  chan struct{}
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+func newBlockingExecutor() *blockingExecutor {
+	return &blockingExecutor{started: make(chan struct{}), release: make(chan struct{}), canceled: make(chan struct{})}
+}
+
+func (e *blockingExecutor) Execute(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	u := updater.NewTaskUpdater(queue, requestContext.TaskId, requestContext.ContextId)
+	u.StartWork()
+	close(e.started)
+	select {
+	case <-e.release:
+		u.Complete()
+		return nil
+	case <-ctx.Done():
+		close(e.canceled)
+		return ctx.Err()
+	}
+}
+
+func (e *blockingExecutor) Cancel(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	return nil
+}
+
+func TestHandlerShutdown(t *testing.T) {
+	testcases := []struct {
+		name      string
+		release   bool
+		options   []HandlerOption
+		wantErr   bool
+		wantState types.TaskState
+	}{
+		{name: "executor finishes during the grace period", release: true, wantState: types.COMPLETED},
+		{name: "executor canceled after the grace period", wantErr: true, wantState: types.FAILED},
+		{name: "configured shutdown state", options: []HandlerOption{WithShutdownTaskState(types.CANCELED)}, wantErr: true, wantState: types.CANCELED},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := tasks.NewInMemoryTaskStore()
+			executor := newBlockingExecutor()
+			handler := NewDefaultHandler(store, executor, append(tc.options, WithQueueManager(QueueManger{}))...)
+			params := types.MessageSendParam{Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User}}
+
+			sent := make(chan struct{})
+			go func() {
+				defer close(sent)
+				_, _ = handler.OnMessageSend(server.NewCallContext(context.Background()), params)
+			}()
+			<-executor.started
+			require.Eventually(t, func() bool {
+				task, err := store.Get(context.Background(), "1")
+				return err == nil && task != nil
+			}, time.Second, 10*time.Millisecond)
+
+			if tc.release {
+				close(executor.release)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := handler.Shutdown(ctx)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				<-executor.canceled
+			} else {
+				assert.NoError(t, err)
+			}
+			<-sent
+
+			task, err := store.Get(context.Background(), "1")
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantState, task.Status.State)
+
+			_, err = handler.OnMessageSend(server.NewCallContext(context.Background()), params)
+			assert.ErrorIs(t, err, ErrShuttingDown, "new tasks are rejected")
+		})
+	}
+}
+
+func TestServerShutdownStream(t *testing.T) {
+	executor := newBlockingExecutor()
+	handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), executor, WithQueueManager(QueueManger{}))
+	srv := NewServer("/card", "/", mockAgentCard, handler)
+	h, err := srv.Handler()
+	require.NoError(t, err)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	body, err := json.Marshal(types.JSONRPCRequest{
+		Id:     "1",
+		Method: types.MethodMessageStream,
+		Params: types.MessageSendParam{Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User}},
+	})
+	require.NoError(t, err)
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	<-executor.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, srv.Shutdown(ctx), "the executor did not finish in time")
+
+	var frames []types.JSONRPCResponse
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var frame types.JSONRPCResponse
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &frame))
+		frames = append(frames, frame)
+	}
+	require.NotEmpty(t, frames)
+	assert.Equal(t, types.ShuttingDownError(), frames[len(frames)-1].Error, "the stream ends with a final frame")
+
+	body, err = json.Marshal(types.JSONRPCRequest{
+		Id:     "2",
+		Method: types.MethodMessageSend,
+		Params: types.MessageSendParam{Message: &types.Message{TaskID: "3", ContextID: "4", Role: types.User}},
+	})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+	var rejected types.JSONRPCResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&rejected))
+	assert.Equal(t, types.ShuttingDownError(), rejected.Error)
+}
+
+func TestServerStartStopsWithContext(t *testing.T) {
+	handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+	srv := NewServer("/card", "/", mockAgentCard, handler, WithShutdownTimeout(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Start(ctx, 0)
+	}()
+	require.Eventually(t, func() bool {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return srv.httpServer != nil
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start did not return after the context was canceled")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+const (
+	defaultMaxRequestBytes = 10 << 20 // 10 MiB, for messages carrying files
+	defaultMaxRequestDepth = 64
+)
+
+// rpcRequest is a JSON-RPC request whose params are decoded by the handler of its method.
+type rpcRequest struct {
+	Id     string          `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// decodeRequest reads the request body within the size and nesting limits of the server,
+// and decodes the JSON-RPC envelope. Params are decoded later, by the handler of the method.
+func (s *Server) decodeRequest(w http.ResponseWriter, r *http.Request) (rpcRequest, *types.JSONRPCError) {
+	var request rpcRequest
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.readLimit()))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return request, types.InvalidRequestError(fmt.Sprintf("Request body exceeds the limit of %d bytes", maxBytesErr.Limit))
+		}
+		return request, types.JSONParseError(err)
+	}
+	if s.maxRequestDepth > 0 && exceedsDepth(body, s.maxRequestDepth) {
+		return request, types.InvalidRequestError(fmt.Sprintf("Request nesting exceeds the limit of %d levels", s.maxRequestDepth))
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return request, types.JSONParseError(err)
+	}
+	if limit := s.methodLimit(request.Method); limit > 0 && int64(len(body)) > limit {
+		return request, types.InvalidRequestError(fmt.Sprintf("Request body exceeds the limit of %d bytes for %s", limit, request.Method))
+	}
+	return request, nil
+}
+
+// methodLimit returns the size limit of request bodies for the method, none if zero.
+func (s *Server) methodLimit(method string) int64 {
+	if limit, ok := s.methodMaxRequestBytes[method]; ok {
+		return limit
+	}
+	return s.maxRequestBytes
+}
+
+// readLimit returns the largest size limit, bounding what is read before the method is known.
+func (s *Server) readLimit() int64 {
+	limit := s.maxRequestBytes
+	for _, methodLimit := range s.methodMaxRequestBytes {
+		if methodLimit <= 0 || limit <= 0 {
+			limit = 0
+			break
+		}
+		limit = max(limit, methodLimit)
+	}
+	if limit <= 0 {
+		return math.MaxInt64
+	}
+	return limit
+}
+
+// exceedsDepth reports whether the JSON document nests arrays and objects deeper than the limit.
+// Malformed documents are left to the decoder.
+func exceedsDepth(data []byte, limit int) bool {
+	depth := 0
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++ // Skips the escaped character
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > limit {
+				return true
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return false
+}
+
+// WithMaxRequestBytes sets the size limit of request bodies, for the methods without their own
+// limit set by WithMethodMaxRequestBytes. By default, it is 10 MiB. Zero disables the limit.
+func WithMaxRequestBytes(limit int64) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.maxRequestBytes = limit
+	})
+}
+
+// WithMethodMaxRequestBytes sets the size limit of request bodies for a method, such as a small
+// limit for types.MethodTasksGet and a larger one for types.MethodMessageSend. Zero disables it.
+func WithMethodMaxRequestBytes(method string, limit int64) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		if server.methodMaxRequestBytes == nil {
+			server.methodMaxRequestBytes = make(map[string]int64)
+		}
+		server.methodMaxRequestBytes[method] = limit
+	})
+}
+
+// WithMaxRequestDepth sets how deeply arrays and objects may nest in request bodies.
+// By default, it is 64. Zero disables the limit.
+func WithMaxRequestDepth(depth int) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.maxRequestDepth = depth
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestRequestLimits(t *testing.T) {
+	send := `{"id":"1","method":"message/send","params":{"message":{"task_id":"1","context_id":"2","role":"user","parts":[{"kind":"text","text":"` +
+		strings.Repeat("a", 1024) + `"}]}}}`
+	get := `{"id":"1","method":"tasks/get","params":{"id":"1","metadata":{"padding":"` + strings.Repeat("a", 1024) + `"}}}`
+	nested := func(depth int) string {
+		return `{"id":"1","method":"tasks/get","params":{"id":"1","metadata":{"a":` +
+			strings.Repeat("[", depth) + strings.Repeat("]", depth) + `}}}`
+	}
+
+	testcases := []struct {
+		name        string
+		options     []ServerConfigOption
+		body        string
+		wantCode    types.ErrorCode
+		wantMessage string
+	}{
+		{name: "within the default limits", body: send},
+		{
+			name:        "body exceeds the limit",
+			options:     []ServerConfigOption{WithMaxRequestBytes(512)},
+			body:        send,
+			wantCode:    types.ErrorCodeInvalidRequest,
+			wantMessage: "Request body exceeds the limit of 512 bytes",
+		},
+		{
+			name:    "method limit above the default",
+			options: []ServerConfigOption{WithMaxRequestBytes(512), WithMethodMaxRequestBytes(types.MethodMessageSend, 4096)},
+			body:    send,
+		},
+		{
+			name:        "method limit below the default",
+			options:     []ServerConfigOption{WithMethodMaxRequestBytes(types.MethodTasksGet, 256)},
+			body:        get,
+			wantCode:    types.ErrorCodeInvalidRequest,
+			wantMessage: "Request body exceeds the limit of 256 bytes for tasks/get",
+		},
+		{
+			name:        "nesting exceeds the limit",
+			options:     []ServerConfigOption{WithMaxRequestDepth(8)},
+			body:        nested(6),
+			wantCode:    types.ErrorCodeInvalidRequest,
+			wantMessage: "Request nesting exceeds the limit of 8 levels",
+		},
+		{
+			name:    "brackets in strings are not nesting",
+			options: []ServerConfigOption{WithMaxRequestDepth(8)},
+			body:    `{"id":"1","method":"message/send","params":{"message":{"task_id":"1","context_id":"2","role":"user","parts":[{"kind":"text","text":"[[[[[[[[\"[[[["}]}}}`,
+		},
+		{
+			name:     "malformed body",
+			body:     `{"id":"1","method":`,
+			wantCode: types.ErrorCodeParseError,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+			srv := NewServer("/card", "/", mockAgentCard, handler, tc.options...)
+			h, err := srv.Handler()
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body)))
+
+			var resp types.JSONRPCResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			if tc.wantCode == 0 {
+				assert.Nil(t, resp.Error)
+				return
+			}
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, tc.wantCode, resp.Error.Code)
+			if tc.wantMessage != "" {
+				assert.Equal(t, tc.wantMessage, resp.Error.Message)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package handler
 
 import (
 	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/scheduler"
 	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
 	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/aggregator"
 	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/manager"
@@ -60,3 +61,18 @@ func WithPushNotifier(pushNotifier tasks.PushNotifier) HandlerOption {
 		d.pushNotifier = pushNotifier
 	})
 }
+
+// WithAuthorizer sets the Authorizer consulted before every operation of the handler.
+func WithAuthorizer(authorizer Authorizer) HandlerOption {
+	return HandlerOptionFunc(func(d *DefaultHandler) {
+		d.authorizer = authorizer
+	})
+}
+
+// WithScheduler sets the scheduler running the agent executors, such as a scheduler.Pool
+// bounding how many tasks run at once. By default, every executor starts right away.
+func WithScheduler(s scheduler.Scheduler) HandlerOption {
+	return HandlerOptionFunc(func(d *DefaultHandler) {
+		d.scheduler = s
+	})
+}
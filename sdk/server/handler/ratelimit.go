@@ -0,0 +1,283 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+const (
+	defaultTaskRetryAfter = time.Second
+	bucketSweepInterval   = time.Minute
+)
+
+// PreAuthMethod is the method every request is limited under before it is authenticated and
+// decoded, attributed to the principal of an unauthenticated request, so that failed
+// authentications and malformed requests are limited too. Its limit is the Default one unless
+// RateLimitConfig.Methods sets one, such as a higher one for the clients behind a shared proxy.
+const PreAuthMethod = "a2a.preAuth"
+
+// RateLimiter limits the requests of each principal, the client a request is attributed to.
+type RateLimiter interface {
+	// Allow reports whether the principal may call the method now, or else how long it should wait.
+	Allow(ctx context.Context, principal string, method string) (ok bool, retryAfter time.Duration)
+	// AcquireTask reserves a slot for a new task of the principal, freed by calling release once
+	// the task stops running, or else reports how long the principal should wait.
+	AcquireTask(ctx context.Context, principal string) (release func(), ok bool, retryAfter time.Duration)
+}
+
+// PrincipalFunc returns the principal a request is attributed to. The user is nil if the
+// request was not authenticated, and the card is the agent card the request was served with.
+type PrincipalFunc func(r *http.Request, user auth.User, card types.AgentCard) string
+
+// RateLimit is a token bucket, refilled with Rate tokens per second up to Burst tokens.
+// Each request takes a token.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitConfig configures the InMemoryRateLimiter. Zero values disable the limits.
+type RateLimitConfig struct {
+	Methods              map[string]RateLimit // Limits of each principal by method
+	Default              RateLimit            // Limit of each principal for the methods without their own
+	MaxTasksPerPrincipal int                  // Concurrent running tasks of each principal
+	MaxTasks             int                  // Concurrent running tasks of all principals
+	TaskRetryAfter       time.Duration        // Delay suggested when a task limit is reached, 1 second by default
+}
+
+// InMemoryRateLimiter is a RateLimiter keeping its buckets and task counts in memory,
+// for a single server process.
+type InMemoryRateLimiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+	tasks     map[string]int // Running tasks by principal
+	total     int            // Running tasks of all principals
+}
+
+type bucketKey struct {
+	principal string
+	method    string
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewInMemoryRateLimiter creates an InMemoryRateLimiter with the limits of the config.
+func NewInMemoryRateLimiter(config RateLimitConfig) *InMemoryRateLimiter {
+	if config.TaskRetryAfter <= 0 {
+		config.TaskRetryAfter = defaultTaskRetryAfter
+	}
+	return &InMemoryRateLimiter{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[bucketKey]*bucket),
+		tasks:   make(map[string]int),
+	}
+}
+
+// Allow takes a token from the bucket of the principal for the method.
+func (l *InMemoryRateLimiter) Allow(ctx context.Context, principal string, method string) (bool, time.Duration) {
+	limit, ok := l.config.Methods[method]
+	if !ok {
+		limit = l.config.Default
+	}
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+	burst := float64(max(limit.Burst, 1))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+	key := bucketKey{principal: principal, method: method}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// sweep drops the buckets unused for a while, which are full again, so that the buckets of
+// principals that stopped calling do not pile up.
+func (l *InMemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= bucketSweepInterval {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// AcquireTask reserves a task slot within the limits of the principal and of all principals.
+func (l *InMemoryRateLimiter) AcquireTask(ctx context.Context, principal string) (func(), bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if (l.config.MaxTasks > 0 && l.total >= l.config.MaxTasks) ||
+		(l.config.MaxTasksPerPrincipal > 0 && l.tasks[principal] >= l.config.MaxTasksPerPrincipal) {
+		return nil, false, l.config.TaskRetryAfter
+	}
+	l.total++
+	l.tasks[principal]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.total--
+			if l.tasks[principal]--; l.tasks[principal] <= 0 {
+				delete(l.tasks, principal)
+			}
+		})
+	}, true, 0
+}
+
+// DefaultPrincipal attributes a request to its authenticated user, else to its remote IP
+// address. Credentials that no authenticator verified, such as API keys, are not used, since a
+// client could send a new one with every request to get a fresh budget.
+func DefaultPrincipal(r *http.Request, user auth.User, card types.AgentCard) string {
+	if user != nil && user.IsAuthenticated() {
+		return "user:" + user.UserName()
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// limitClient applies the rate limiter to a request not yet authenticated, under PreAuthMethod.
+// It returns false once it answered a rejected request.
+func (s *Server) limitClient(w http.ResponseWriter, r *http.Request, card types.AgentCard) bool {
+	if s.rateLimiter == nil {
+		return true
+	}
+	principal := s.principal(r, nil, card)
+	if ok, retryAfter := s.rateLimiter.Allow(r.Context(), principal, PreAuthMethod); !ok {
+		s.sendRateLimited(w, "", retryAfter, types.RateLimitedError())
+		return false
+	}
+	return true
+}
+
+// principal returns the principal the request is attributed to.
+func (s *Server) principal(r *http.Request, user auth.User, card types.AgentCard) string {
+	if s.principalFunc == nil {
+		return DefaultPrincipal(r, user, card)
+	}
+	return s.principalFunc(r, user, card)
+}
+
+// limit applies the rate limiter to the request, and reserves a task slot for the methods
+// starting a task. It returns the request carrying the slot and the function freeing the slot
+// unless an executor claimed it, or false once it answered a rejected request.
+func (s *Server) limit(w http.ResponseWriter, r *http.Request, card types.AgentCard, user auth.User, request *rpcRequest) (*http.Request, func(), bool) {
+	if s.rateLimiter == nil {
+		return r, func() {}, true
+	}
+	principal := s.principal(r, user, card)
+
+	if ok, retryAfter := s.rateLimiter.Allow(r.Context(), principal, request.Method); !ok {
+		s.sendRateLimited(w, request.Id, retryAfter, types.RateLimitedError())
+		return r, nil, false
+	}
+	if request.Method != types.MethodMessageSend && request.Method != types.MethodMessageStream {
+		return r, func() {}, true
+	}
+	release, ok, retryAfter := s.rateLimiter.AcquireTask(r.Context(), principal)
+	if !ok {
+		s.sendRateLimited(w, request.Id, retryAfter, types.TooManyTasksError())
+		return r, nil, false
+	}
+	slot := &taskSlot{release: release}
+	return r.WithContext(context.WithValue(r.Context(), taskSlotKey{}, slot)), slot.releaseUnclaimed, true
+}
+
+// sendRateLimited answers a rejected request with 429 Too Many Requests and a Retry-After header.
+func (s *Server) sendRateLimited(w http.ResponseWriter, id string, retryAfter time.Duration, err *types.JSONRPCError) {
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	s.sendError(w, id, err)
+}
+
+// taskSlot is the task slot reserved for a request. The executor of the task claims it, to
+// free it once it stops running, otherwise it is freed once the request is served.
+type taskSlot struct {
+	release func()
+	claimed atomic.Bool
+}
+
+type taskSlotKey struct{}
+
+// claimTaskSlot claims the task slot of the request, returning the function freeing it.
+func claimTaskSlot(ctx context.Context) func() {
+	slot, ok := ctx.Value(taskSlotKey{}).(*taskSlot)
+	if !ok || !slot.claimed.CompareAndSwap(false, true) {
+		return func() {}
+	}
+	return slot.release
+}
+
+func (s *taskSlot) releaseUnclaimed() {
+	if s.claimed.CompareAndSwap(false, true) {
+		s.release()
+	}
+}
+
+// WithRateLimiter sets the rate limiter of the server, such as an InMemoryRateLimiter.
+// Rejected requests get a JSON-RPC error with 429 Too Many Requests and a Retry-After header.
+func WithRateLimiter(limiter RateLimiter) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.rateLimiter = limiter
+	})
+}
+
+// WithPrincipalFunc sets how requests are attributed to principals by the rate limiter,
+// such as by a header set by a proxy. By default, it is DefaultPrincipal.
+func WithPrincipalFunc(fn PrincipalFunc) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.principalFunc = fn
+	})
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestInMemoryRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewInMemoryRateLimiter(RateLimitConfig{
+		Methods:              map[string]RateLimit{types.MethodTasksGet: {Rate: 2, Burst: 2}},
+		MaxTasksPerPrincipal: 1,
+		MaxTasks:             2,
+	})
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	allow := func(principal string) (bool, time.Duration) {
+		return limiter.Allow(ctx, principal, types.MethodTasksGet)
+	}
+	for range 2 {
+		ok, _ := allow("a")
+		assert.True(t, ok, "within the burst")
+	}
+	ok, retryAfter := allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+	ok, _ = allow("b")
+	assert.True(t, ok, "each principal has its own bucket")
+	ok, _ = limiter.Allow(ctx, "a", types.MethodMessageSend)
+	assert.True(t, ok, "the method has no limit")
+
+	now = now.Add(250 * time.Millisecond)
+	ok, retryAfter = allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, 250*time.Millisecond, retryAfter)
+	now = now.Add(250 * time.Millisecond)
+	ok, _ = allow("a")
+	assert.True(t, ok, "a token was refilled")
+
+	releaseA, ok, _ := limiter.AcquireTask(ctx, "a")
+	require.True(t, ok)
+	_, ok, retryAfter = limiter.AcquireTask(ctx, "a")
+	assert.False(t, ok, "limit per principal")
+	assert.Equal(t, time.Second, retryAfter)
+	_, ok, _ = limiter.AcquireTask(ctx, "b")
+	require.True(t, ok)
+	_, ok, _ = limiter.AcquireTask(ctx, "c")
+	assert.False(t, ok, "limit of all principals")
+
+	releaseA()
+	releaseA()
+	_, ok, _ = limiter.AcquireTask(ctx, "c")
+	assert.True(t, ok, "the slot was released once")
+	_, ok, _ = limiter.AcquireTask(ctx, "d")
+	assert.False(t, ok, "releasing twice frees a single slot")
+}
+
+func TestDefaultPrincipal(t *testing.T) {
+	card := mockAgentCard
+	card.SecuritySchemes = map[string]types.SecurityScheme{
+		"key": types.APIKeySecurityScheme{In: types.InHeader, Name: "X-API-Key"},
+	}
+	testcases := []struct {
+		name   string
+		user   auth.User
+		header map[string]string
+		want   string
+	}{
+		{name: "authenticated user", user: auth.BasicUser{Name: "alice"}, header: map[string]string{"X-API-Key": "secret"}, want: "user:alice"},
+		{name: "unverified api key", header: map[string]string{"X-API-Key": "secret"}, want: "ip:192.0.2.1"},
+		{name: "anonymous user", user: auth.AnonymousUser{}, want: "ip:192.0.2.1"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			for key, value := range tc.header {
+				r.Header.Set(key, value)
+			}
+			assert.Equal(t, tc.want, DefaultPrincipal(r, tc.user, card))
+		})
+	}
+}
+
+func TestServerRateLimitRotatingKeys(t *testing.T) {
+	card := mockAgentCard
+	card.SecuritySchemes = map[string]types.SecurityScheme{
+		"key": types.APIKeySecurityScheme{Type: types.APIKEY, In: types.InHeader, Name: "X-API-Key"},
+	}
+	handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+	srv := NewServer("/card", "/", card, handler, WithRateLimiter(NewInMemoryRateLimiter(RateLimitConfig{
+		Default: RateLimit{Rate: 0.1, Burst: 1},
+	})))
+	h, err := srv.Handler()
+	require.NoError(t, err)
+
+	body, err := json.Marshal(types.JSONRPCRequest{Id: "1", Method: types.MethodTasksGet, Params: types.TaskQueryParams{Id: "1"}})
+	require.NoError(t, err)
+	codes := make([]int, 0, 3)
+	for _, key := range []string{"key-1", "key-2", "key-3"} {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests}, codes,
+		"unverified keys do not get a budget of their own")
+}
+
+func TestServerRateLimitFailedAuthentication(t *testing.T) {
+	var attempts int
+	bearer := auth.NewBearerAuthenticator(func(ctx context.Context, token string, scopes []string) (auth.User, error) {
+		attempts++
+		if token != "valid-token" {
+			return nil, auth.ErrInvalidCredentials
+		}
+		return auth.BasicUser{Name: "token-user"}, nil
+	})
+	handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+	srv := NewServer("/card", "/", newSecuredCard(), handler, WithAuthenticator("bearer", bearer),
+		WithRateLimiter(NewInMemoryRateLimiter(RateLimitConfig{
+			Methods: map[string]RateLimit{PreAuthMethod: {Rate: 0.1, Burst: 2}},
+		})))
+	h, err := srv.Handler()
+	require.NoError(t, err)
+
+	body, err := json.Marshal(types.JSONRPCRequest{Id: "1", Method: types.MethodTasksGet, Params: types.TaskQueryParams{Id: "1"}})
+	require.NoError(t, err)
+	codes := make([]int, 0, 4)
+	for _, token := range []string{"guess-1", "guess-2", "guess-3", "valid-token"} {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusTooManyRequests}, codes,
+		"the client is limited before its credentials are checked")
+	assert.Equal(t, 2, attempts)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("{")))
+	req.RemoteAddr = "192.0.2.2:1234"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "other clients have their own budget")
+}
+
+func TestServerRateLimit(t *testing.T) {
+	executor := newBlockingExecutor()
+	handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), executor, WithQueueManager(QueueManger{}))
+	srv := NewServer("/card", "/", mockAgentCard, handler, WithRateLimiter(NewInMemoryRateLimiter(RateLimitConfig{
+		Methods:              map[string]RateLimit{types.MethodTasksGet: {Rate: 0.1, Burst: 1}},
+		MaxTasksPerPrincipal: 1,
+	})))
+	h, err := srv.Handler()
+	require.NoError(t, err)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	post := func(method string, params any) (*http.Response, types.JSONRPCResponse) {
+		body, err := json.Marshal(types.JSONRPCRequest{Id: "1", Method: method, Params: params})
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		if resp.Header.Get("Content-Type") != "application/json" {
+			return resp, types.JSONRPCResponse{}
+		}
+		defer resp.Body.Close()
+		var rpcResp types.JSONRPCResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&rpcResp))
+		return resp, rpcResp
+	}
+
+	resp, _ := post(types.MethodTasksGet, types.TaskQueryParams{Id: "1"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, rpcResp := post(types.MethodTasksGet, types.TaskQueryParams{Id: "1"})
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "10", resp.Header.Get("Retry-After"))
+	assert.Equal(t, types.RateLimitedError(), rpcResp.Error)
+
+	params := types.MessageSendParam{Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User}}
+	stream, _ := post(types.MethodMessageStream, params)
+	defer stream.Body.Close()
+	<-executor.started
+	resp, rpcResp = post(types.MethodMessageSend, types.MessageSendParam{Message: &types.Message{TaskID: "3", ContextID: "4", Role: types.User}})
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	assert.Equal(t, types.TooManyTasksError(), rpcResp.Error)
+
+	close(executor.release)
+	require.Eventually(t, func() bool {
+		_, ok, _ := srv.rateLimiter.AcquireTask(context.Background(), "ip:127.0.0.1")
+		return ok
+	}, time.Second, 10*time.Millisecond, "the slot is freed once the executor stops")
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// PanicError describes a panic of an agent executor, recovered by the handler.
+type PanicError struct {
+	TaskId string
+	Value  any    // Value passed to panic
+	Stack  []byte // Stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("agent executor panicked on task %s: %v", e.TaskId, e.Value)
+}
+
+// PanicHandler is called with every recovered panic of an agent executor, to report it to
+// an error tracker for example. The task is already marked FAILED.
+type PanicHandler func(ctx context.Context, requestContext *execution.RequestContext, err *PanicError)
+
+// recoverExecutor recovers a panic of the executor running the task. The task is marked FAILED,
+// with a final event ending the streams and the message/send calls waiting for the task.
+// It must be deferred by the goroutine running the executor, before the queue is closed.
+func (d *DefaultHandler) recoverExecutor(ctx context.Context, reqCtx *execution.RequestContext, queue *event.Queue) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	panicErr := &PanicError{TaskId: reqCtx.TaskId, Value: value, Stack: debug.Stack()}
+	log.Errorf("%v\n%s", panicErr, panicErr.Stack)
+
+	ctx = context.WithoutCancel(ctx)
+	d.failTask(ctx, reqCtx, queue, fmt.Sprintf("The agent failed unexpectedly: %v", value))
+	if d.panicHandler != nil {
+		d.panicHandler(ctx, reqCtx, panicErr)
+	}
+}
+
+// recoverCancel recovers a panic of the executor canceling the task, reported as an error.
+func (d *DefaultHandler) recoverCancel(ctx context.Context, reqCtx *execution.RequestContext, queue *event.Queue) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	panicErr := &PanicError{TaskId: reqCtx.TaskId, Value: value, Stack: debug.Stack()}
+	log.Errorf("%v\n%s", panicErr, panicErr.Stack)
+	queue.EnqueueError(panicErr)
+	if d.panicHandler != nil {
+		d.panicHandler(context.WithoutCancel(ctx), reqCtx, panicErr)
+	}
+}
+
+// failTask marks the task FAILED with the reason as status message, with a final event ending
+// the streams and the message/send calls waiting for the task.
+func (d *DefaultHandler) failTask(ctx context.Context, reqCtx *execution.RequestContext, queue *event.Queue, reason string) {
+	u := updater.NewTaskUpdater(queue, reqCtx.TaskId, reqCtx.ContextId)
+	status := failedStatus(u, reason)
+	u.Failed(updater.WithMessage(status.Message), updater.WithTimestamp(status.TimeStamp))
+
+	// The queue may be full or no longer consumed, the store is updated directly as well.
+	d.markFailed(ctx, reqCtx, status)
+}
+
+// markFailed saves the FAILED status of the task to the store, unless the task already ended.
+func (d *DefaultHandler) markFailed(ctx context.Context, reqCtx *execution.RequestContext, status types.TaskStatus) {
+	if err := d.markTask(ctx, reqCtx.TaskId, status); err != nil {
+		log.Errorf("failed to mark task %s failed: %v", reqCtx.TaskId, err)
+	}
+}
+
+// failedStatus returns the FAILED status with the reason as agent message.
+func failedStatus(u *updater.TaskUpdater, reason string) types.TaskStatus {
+	message := u.NewAgentMessage([]types.Part{&types.TextPart{Kind: types.PartTypeText, Text: reason}})
+	return types.TaskStatus{State: types.FAILED, Message: message, TimeStamp: time.Now().Format(time.RFC3339)}
+}
+
+// WithPanicHandler sets the function called with every recovered panic of an agent executor.
+// By default, panics are logged with their stack trace.
+func WithPanicHandler(fn PanicHandler) HandlerOption {
+	return HandlerOptionFunc(func(d *DefaultHandler) {
+		d.panicHandler = fn
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server"
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+type panickingExecutor struct{}
+
+func (e panickingExecutor) Execute(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	updater.NewTaskUpdater(queue, requestContext.TaskId, requestContext.ContextId).StartWork()
+	var tools map[string]func()
+	tools["search"]()
+	return nil
+}
+
+func (e panickingExecutor) Cancel(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	panic("cancel is not implemented")
+}
+
+func TestExecutorPanic(t *testing.T) {
+	testcases := []struct {
+		name string
+		send func(handler *DefaultHandler, params types.MessageSendParam) *types.TaskStatus
+	}{
+		{
+			name: "message/send",
+			send: func(handler *DefaultHandler, params types.MessageSendParam) *types.TaskStatus {
+				ev, err := handler.OnMessageSend(server.NewCallContext(context.Background()), params)
+				require.NoError(t, err)
+				task, ok := ev.(*types.Task)
+				require.True(t, ok)
+				return &task.Status
+			},
+		},
+		{
+			name: "message/stream",
+			send: func(handler *DefaultHandler, params types.MessageSendParam) *types.TaskStatus {
+				var last types.StreamEvent
+				for ev := range handler.OnMessageSendStream(server.NewCallContext(context.Background()), params) {
+					last = ev
+				}
+				require.Equal(t, types.EventDone, last.Type, "the stream ends with a final event")
+				update, ok := last.Event.(*types.TaskStatusUpdateEvent)
+				require.True(t, ok)
+				return &update.Status
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := tasks.NewInMemoryTaskStore()
+			var recovered *PanicError
+			handler := NewDefaultHandler(store, panickingExecutor{}, WithQueueManager(QueueManger{}),
+				WithPanicHandler(func(ctx context.Context, requestContext *execution.RequestContext, err *PanicError) {
+					recovered = err
+				}))
+
+			status := tc.send(handler, types.MessageSendParam{Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User}})
+			assert.Equal(t, types.FAILED, status.State)
+			require.NotNil(t, status.Message)
+			require.Len(t, status.Message.Parts, 1)
+			assert.Contains(t, status.Message.Parts[0].(*types.TextPart).Text, "nil pointer dereference")
+
+			require.NoError(t, handler.Shutdown(context.Background()), "the executor is no longer running")
+			require.NotNil(t, recovered)
+			assert.Equal(t, "1", recovered.TaskId)
+			assert.Contains(t, string(recovered.Stack), "panickingExecutor")
+
+			task, err := store.Get(context.Background(), "1")
+			require.NoError(t, err)
+			assert.Equal(t, types.FAILED, task.Status.State)
+		})
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// DefaultIndexPath is the path of the discovery index listing the agents hosted by a Router.
+const DefaultIndexPath = "/.well-known/agents.json"
+
+// Router serves several agents from a single HTTP server, each under its own path prefix or
+// hostname. Every agent is served by its own Server, so it keeps its own handler, task store,
+// queue manager and executor. The URL of each agent card is rewritten to the URL the agent is
+// mounted at, and the discovery index lists the cards of all the hosted agents.
+//
+// The URLs of the cards are built from the URL set by WithPublicURL, or else from the host of
+// the request when it is trusted: a hostname an agent is mounted on, or a host allowed by
+// WithAllowedHosts. Requests for other hosts get the cards with their URLs unchanged.
+type Router struct {
+	indexPath       string          // Path of the discovery index
+	publicURL       *url.URL        // URL the router is reachable at, by default the URL of the request
+	allowedHosts    map[string]bool // Hosts of the requests trusted to build the URLs of the cards
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
+
+	mu         sync.RWMutex
+	agents     []*mountedAgent          // Agents in mount order, as listed by the index
+	hosts      map[string]*mountedAgent // Agents mounted on a hostname
+	mux        *http.ServeMux           // Agents mounted on a path prefix
+	httpServer *http.Server             // Server launched by Start
+}
+
+// mountedAgent is an agent served by a Router.
+type mountedAgent struct {
+	path    string // Path prefix, empty for an agent mounted on a hostname
+	host    string // Hostname, empty for an agent mounted on a path prefix
+	server  *Server
+	handler http.Handler // Routes of the server
+}
+
+// RouterOption configures a Router.
+type RouterOption interface {
+	Option(r *Router)
+}
+
+// RouterOptionFunc is a function type for RouterOption.
+type RouterOptionFunc func(r *Router)
+
+func (fn RouterOptionFunc) Option(r *Router) {
+	fn(r)
+}
+
+// NewRouter creates a Router without agents, see Mount and MountHost.
+func NewRouter(options ...RouterOption) *Router {
+	r := &Router{
+		indexPath:       DefaultIndexPath,
+		readTimeout:     defaultReadTimeout,
+		writeTimeout:    defaultWriteTimeout,
+		idleTimeout:     defaultIdleTimeout,
+		shutdownTimeout: defaultShutdownTimeout,
+		hosts:           make(map[string]*mountedAgent),
+		mux:             http.NewServeMux(),
+	}
+	for _, opt := range options {
+		opt.Option(r)
+	}
+	return r
+}
+
+// Mount serves the agent under the path prefix: its card at prefix/.well-known/agent.json,
+// its extended card at prefix/agent/authenticatedExtendedCard and its JSON-RPC endpoint at
+// prefix/, which is the URL set on its card. The server options configure the agent only.
+// It returns an error if the card or the extended card has errors, see Server.Validate.
+func (r *Router) Mount(prefix string, card types.AgentCard, handler Handler, options ...ServerConfigOption) error {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if !strings.HasPrefix(prefix, "/") {
+		return fmt.Errorf("invalid mount path %q, expected an absolute path", prefix)
+	}
+	if prefix == strings.TrimSuffix(r.indexPath, "/") {
+		return fmt.Errorf("mount path %q conflicts with the discovery index", prefix)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, agent := range r.agents {
+		if agent.host == "" && agent.path == prefix {
+			return fmt.Errorf("an agent is already mounted at %q", prefix)
+		}
+	}
+	agent := &mountedAgent{path: prefix}
+	agent.server = r.newServer(agent, prefix+types.AgentCardPath, prefix+"/", prefix+types.ExtendedAgentCardPath,
+		card, handler, options)
+	if err := agent.server.Validate(context.Background()); err != nil {
+		return fmt.Errorf("agent mounted at %s: %w", agent.mountPoint(), err)
+	}
+	r.mux.Handle(prefix+"/", agent.handler)
+	r.agents = append(r.agents, agent)
+	return nil
+}
+
+// MountHost serves the agent on requests for the hostname, at the default paths: its card
+// at /.well-known/agent.json and its JSON-RPC endpoint at /. The discovery index remains
+// served on every hostname. The server options configure the agent only, and the cards are
+// validated as by Mount.
+func (r *Router) MountHost(host string, card types.AgentCard, handler Handler, options ...ServerConfigOption) error {
+	host = strings.ToLower(host)
+	if host == "" || strings.ContainsAny(host, ":/") {
+		return fmt.Errorf("invalid mount hostname %q, expected a hostname without port", host)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.hosts[host]; ok {
+		return fmt.Errorf("an agent is already mounted on %q", host)
+	}
+	agent := &mountedAgent{host: host}
+	agent.server = r.newServer(agent, types.AgentCardPath, "/", types.ExtendedAgentCardPath, card, handler, options)
+	if err := agent.server.Validate(context.Background()); err != nil {
+		return fmt.Errorf("agent mounted at %s: %w", agent.mountPoint(), err)
+	}
+	r.hosts[host] = agent
+	r.agents = append(r.agents, agent)
+	return nil
+}
+
+// newServer creates the server of the agent. The paths are set after the options, since they
+// are given by the mount point.
+func (r *Router) newServer(agent *mountedAgent, cardPath, basePath, extendedCardPath string,
+	card types.AgentCard, handler Handler, options []ServerConfigOption) *Server {
+	svc := NewServer(cardPath, basePath, card, handler, options...)
+	svc.agentCardPath = cardPath
+	svc.basePath = basePath
+	svc.extendedCardPath = extendedCardPath
+	agent.handler = svc.httpHandler()
+	svc.cardURL = func(ctx context.Context) (string, bool) {
+		base, ok := ctx.Value(baseURLKey{}).(*url.URL)
+		if !ok {
+			base = r.publicURL
+		}
+		if base == nil {
+			return "", false
+		}
+		return agent.url(base), true
+	}
+	return svc
+}
+
+// url returns the URL of the JSON-RPC endpoint of the agent, relative to the URL of the router.
+func (a *mountedAgent) url(base *url.URL) string {
+	u := *base
+	if a.host != "" {
+		u.Host = a.host
+		if port := base.Port(); port != "" {
+			u.Host = net.JoinHostPort(a.host, port)
+		}
+	}
+	u.Path = strings.TrimSuffix(base.Path, "/") + a.path + "/"
+	u.RawPath = ""
+	return u.String()
+}
+
+// baseURLKey is the context key of the URL the router is reached at by the current request.
+type baseURLKey struct{}
+
+// ServeHTTP serves the discovery index, then the agent mounted on the hostname of the request
+// if any, then the agent mounted on the longest path prefix matching the request.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if base := r.baseURL(req); base != nil {
+		req = req.WithContext(context.WithValue(req.Context(), baseURLKey{}, base))
+	}
+	if req.URL.Path == r.indexPath {
+		r.handleIndex(w, req)
+		return
+	}
+
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	r.mu.RLock()
+	agent, ok := r.hosts[strings.ToLower(host)]
+	r.mu.RUnlock()
+	if ok {
+		agent.handler.ServeHTTP(w, req)
+		return
+	}
+	r.mux.ServeHTTP(w, req)
+}
+
+// baseURL returns the URL the router is reached at, set by WithPublicURL or given by the request.
+// The host of the request is chosen by the client, so it is only used when it is trusted, and
+// nil is returned otherwise.
+func (r *Router) baseURL(req *http.Request) *url.URL {
+	if r.publicURL != nil {
+		return r.publicURL
+	}
+	if !r.trustedHost(req.Host) {
+		return nil
+	}
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	return &url.URL{Scheme: scheme, Host: req.Host}
+}
+
+// trustedHost reports whether the host of a request is allowed by WithAllowedHosts, or is a
+// hostname an agent is mounted on, without a port.
+func (r *Router) trustedHost(host string) bool {
+	host = strings.ToLower(host)
+	if r.allowedHosts[host] {
+		return true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.hosts[host]
+	return ok
+}
+
+// handleIndex lists the public cards of the hosted agents, with the URLs they are mounted at.
+func (r *Router) handleIndex(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cards := make([]types.AgentCard, 0, len(r.mountedAgents()))
+	for _, agent := range r.mountedAgents() {
+		card, err := agent.server.agentCard(req.Context())
+		if err != nil {
+			log.Errorf("handleIndex | %v", err)
+			continue
+		}
+		cards = append(cards, card)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", defaultCardCacheControl)
+	if err := json.NewEncoder(w).Encode(cards); err != nil {
+		log.Errorf("handleIndex | %v", err)
+	}
+}
+
+func (r *Router) mountedAgents() []*mountedAgent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*mountedAgent(nil), r.agents...)
+}
+
+// Validate validates the cards of every hosted agent, see Server.Validate.
+func (r *Router) Validate(ctx context.Context) error {
+	for _, agent := range r.mountedAgents() {
+		if err := agent.server.Validate(ctx); err != nil {
+			return fmt.Errorf("agent mounted at %s: %w", agent.mountPoint(), err)
+		}
+	}
+	return nil
+}
+
+func (a *mountedAgent) mountPoint() string {
+	if a.host != "" {
+		return a.host
+	}
+	return a.path
+}
+
+// Start launches the HTTP server serving every hosted agent on the specified port, and blocks
+// until the router is shut down. When the context is done, the router is shut down as by
+// Shutdown, waiting for the running executors for the timeout set by WithRouterShutdownTimeout.
+// It refuses to start if a card has errors, and returns nil once shut down gracefully.
+// The router serves plain HTTP: the WithTLS and WithMutualTLS options of the agents are not
+// applied. To serve HTTPS, serve the Router, an http.Handler, from your own http.Server.
+func (r *Router) Start(ctx context.Context, port int) error {
+	if err := r.Validate(ctx); err != nil {
+		return err
+	}
+	for _, agent := range r.mountedAgents() {
+		if agent.server.certFile != "" || agent.server.clientCAs != nil {
+			log.Warnf("the TLS options of the agent mounted at %s are ignored by the router", agent.mountPoint())
+		}
+	}
+	svc := &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      r,
+		ReadTimeout:  r.readTimeout,
+		WriteTimeout: r.writeTimeout,
+		IdleTimeout:  r.idleTimeout,
+	}
+	r.mu.Lock()
+	r.httpServer = svc
+	r.mu.Unlock()
+	log.Infof("Starting HTTP svc on :%d for %d agents", port, len(r.mountedAgents()))
+	return serveUntilDone(ctx, r.shutdownTimeout, r.Shutdown, svc.ListenAndServe)
+}
+
+// Shutdown gracefully shuts the router down: it stops accepting connections and shuts down
+// every hosted agent concurrently, as by Server.Shutdown.
+func (r *Router) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	svc := r.httpServer
+	r.mu.Unlock()
+
+	agents := r.mountedAgents()
+	errs := make([]error, len(agents)+1)
+	var wg sync.WaitGroup
+	for i, agent := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := agent.server.Shutdown(ctx); err != nil {
+				errs[i] = fmt.Errorf("agent mounted at %s: %w", agent.mountPoint(), err)
+			}
+		}()
+	}
+	if svc != nil {
+		if err := svc.Shutdown(ctx); err != nil {
+			_ = svc.Close()
+			errs[len(agents)] = fmt.Errorf("failed to shut down gracefully: %w", err)
+		}
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// WithIndexPath sets the path of the discovery index. By default, it is /.well-known/agents.json.
+func WithIndexPath(path string) RouterOption {
+	return RouterOptionFunc(func(r *Router) {
+		r.indexPath = path
+	})
+}
+
+// WithPublicURL sets the URL the router is reachable at, such as the URL of a reverse proxy,
+// to build the URLs of the agent cards. By default, the scheme and host of the request are used
+// when the host is trusted, see WithAllowedHosts.
+func WithPublicURL(publicURL *url.URL) RouterOption {
+	return RouterOptionFunc(func(r *Router) {
+		r.publicURL = publicURL
+	})
+}
+
+// WithAllowedHosts sets the hosts, with their port if any, that the requests can be made for
+// to build the URLs of the agent cards when WithPublicURL is not set. The hostnames the agents
+// are mounted on are allowed without a port.
+func WithAllowedHosts(hosts ...string) RouterOption {
+	return RouterOptionFunc(func(r *Router) {
+		if r.allowedHosts == nil {
+			r.allowedHosts = make(map[string]bool, len(hosts))
+		}
+		for _, host := range hosts {
+			r.allowedHosts[strings.ToLower(host)] = true
+		}
+	})
+}
+
+// WithRouterTimeouts sets the read, write and idle timeouts of the HTTP server launched by Start.
+func WithRouterTimeouts(read, write, idle time.Duration) RouterOption {
+	return RouterOptionFunc(func(r *Router) {
+		r.readTimeout = read
+		r.writeTimeout = write
+		r.idleTimeout = idle
+	})
+}
+
+// WithRouterShutdownTimeout sets how long the router waits for running executors once the
+// context given to Start is done, before canceling them. By default, it is 30 seconds.
+func WithRouterShutdownTimeout(timeout time.Duration) RouterOption {
+	return RouterOptionFunc(func(r *Router) {
+		r.shutdownTimeout = timeout
+	})
+}
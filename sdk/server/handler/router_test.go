@@ -0,0 +1,144 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestRouter(t *testing.T) {
+	stores := map[string]*tasks.InMemoryTaskStore{}
+	newAgent := func(name string) (types.AgentCard, Handler) {
+		card := mockAgentCard
+		card.Name = name
+		card.URL = "http://localhost:8080/"
+		stores[name] = tasks.NewInMemoryTaskStore()
+		return card, NewDefaultHandler(stores[name], newExecutor(), WithQueueManager(QueueManger{}))
+	}
+
+	router := NewRouter(WithAllowedHosts("agents.example.com", "Agents.example.com:8443", "c.example.com:8443"))
+	card, handler := newAgent("a")
+	require.NoError(t, router.Mount("/agents/a", card, handler))
+	card, handler = newAgent("b")
+	require.NoError(t, router.Mount("/agents/b/", card, handler, WithExtendedAgentCard(card)))
+	card, handler = newAgent("c")
+	require.NoError(t, router.MountHost("c.example.com", card, handler))
+
+	assert.Error(t, router.Mount("/agents/a", card, handler), "the path is taken")
+	assert.Error(t, router.Mount("agents/d", card, handler), "the path is relative")
+	assert.Error(t, router.MountHost("C.example.com", card, handler), "the hostname is taken")
+	assert.Error(t, router.MountHost("d.example.com:8080", card, handler), "the hostname has a port")
+
+	getCard := func(host, path string) types.AgentCard {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = host
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, path)
+		var card types.AgentCard
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&card))
+		return card
+	}
+
+	testcases := []struct {
+		name     string
+		host     string
+		path     string
+		wantName string
+		wantURL  string
+	}{
+		{name: "mounted on a path", host: "agents.example.com:8443", path: "/agents/a" + types.AgentCardPath, wantName: "a", wantURL: "http://agents.example.com:8443/agents/a/"},
+		{name: "trailing slash trimmed", host: "agents.example.com", path: "/agents/b" + types.AgentCardPath, wantName: "b", wantURL: "http://agents.example.com/agents/b/"},
+		{name: "mounted on a hostname", host: "c.example.com:8443", path: types.AgentCardPath, wantName: "c", wantURL: "http://c.example.com:8443/"},
+		{name: "mounted hostname without port", host: "c.example.com", path: types.AgentCardPath, wantName: "c", wantURL: "http://c.example.com/"},
+		{name: "untrusted host", host: "attacker.example.com", path: "/agents/a" + types.AgentCardPath, wantName: "a", wantURL: "http://localhost:8080/"},
+		{name: "untrusted port", host: "c.example.com:6666", path: types.AgentCardPath, wantName: "c", wantURL: "http://localhost:8080/"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			card := getCard(tc.host, tc.path)
+			assert.Equal(t, tc.wantName, card.Name)
+			assert.Equal(t, tc.wantURL, card.URL)
+		})
+	}
+
+	t.Run("discovery index", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, DefaultIndexPath, nil)
+		req.Host = "agents.example.com"
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var cards []types.AgentCard
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&cards))
+		require.Len(t, cards, 3)
+		assert.Equal(t, "http://agents.example.com/agents/a/", cards[0].URL)
+		assert.Equal(t, "http://agents.example.com/agents/b/", cards[1].URL)
+		assert.True(t, cards[1].SupportsAuthenticatedExtendedCard)
+		assert.Equal(t, "http://c.example.com/", cards[2].URL)
+	})
+
+	t.Run("each agent keeps its own task store", func(t *testing.T) {
+		body, err := json.Marshal(types.JSONRPCRequest{
+			Id:     "1",
+			Method: types.MethodMessageSend,
+			Params: types.MessageSendParam{Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User}},
+		})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/agents/b/", bytes.NewReader(body)))
+		var resp types.JSONRPCResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Nil(t, resp.Error)
+
+		task, err := stores["b"].Get(context.Background(), "1")
+		require.NoError(t, err)
+		assert.NotNil(t, task)
+		task, err = stores["a"].Get(context.Background(), "1")
+		require.NoError(t, err)
+		assert.Nil(t, task)
+	})
+
+	t.Run("public url", func(t *testing.T) {
+		publicURL, err := url.Parse("https://gateway.example.com/a2a")
+		require.NoError(t, err)
+		router := NewRouter(WithPublicURL(publicURL), WithIndexPath("/agents"))
+		card, handler := newAgent("d")
+		require.NoError(t, router.Mount("/d", card, handler))
+		assert.Error(t, router.Mount("/agents", card, handler), "the path is the index")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/agents", nil))
+		var cards []types.AgentCard
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&cards))
+		require.Len(t, cards, 1)
+		assert.Equal(t, "https://gateway.example.com/a2a/d/", cards[0].URL)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, router.Shutdown(ctx))
+}
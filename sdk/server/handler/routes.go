@@ -0,0 +1,118 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// Route identifies an endpoint served by the Server.
+type Route string
+
+const (
+	// RouteAgentCard is the agent card endpoint.
+	RouteAgentCard Route = "agent_card"
+	// RouteExtendedAgentCard is the authenticated extended agent card endpoint.
+	RouteExtendedAgentCard Route = "extended_agent_card"
+	// RouteJSONRPC is the JSON-RPC endpoint, including the streaming methods.
+	RouteJSONRPC Route = "jsonrpc"
+)
+
+// Middleware wraps an http.Handler, for logging, CORS or recovery for example.
+type Middleware func(next http.Handler) http.Handler
+
+// Headers returns a middleware setting the given headers on every response.
+func Headers(headers map[string]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for key, value := range headers {
+				w.Header().Set(key, value)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NewHTTPHandler creates a Server and returns the http.Handler serving its endpoints, to be
+// mounted into an existing router or served by an existing http.Server. Use NewServer and
+// Server.Handler instead to keep the Server, for its Shutdown method. It returns an error if
+// the agent card or the extended agent card has errors.
+func NewHTTPHandler(cardPath string, basePath string, card types.AgentCard, handler Handler, options ...ServerConfigOption) (http.Handler, error) {
+	return NewServer(cardPath, basePath, card, handler, options...).Handler()
+}
+
+// Handler returns the http.Handler serving the agent card, the extended agent card if any,
+// and the JSON-RPC endpoint, wrapped by the middlewares of the server. Several servers with
+// different paths can be mounted into the same router. The CORS policy set by WithCORS wraps
+// the middlewares, so preflight requests are answered before them. Like Start, it refuses an
+// agent card or an extended agent card with errors, see Validate.
+func (s *Server) Handler() (http.Handler, error) {
+	if err := s.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return s.httpHandler(), nil
+}
+
+// httpHandler returns the handler serving every route, without validating the cards.
+func (s *Server) httpHandler() http.Handler {
+	handler := chain(s.routes(), s.middlewares)
+	if s.cors != nil {
+		handler = s.cors(handler)
+	}
+	return handler
+}
+
+// routes returns the handler serving every route wrapped by its own middlewares.
+func (s *Server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(s.agentCardPath, s.route(RouteAgentCard, http.HandlerFunc(s.handleGetAgentCard)))
+	if s.extendedCard != nil {
+		mux.Handle(s.extendedCardPath, s.route(RouteExtendedAgentCard, http.HandlerFunc(s.handleGetExtendedAgentCard)))
+	}
+	mux.Handle(s.basePath, s.route(RouteJSONRPC, s))
+	return mux
+}
+
+func (s *Server) route(route Route, handler http.Handler) http.Handler {
+	return chain(handler, s.routeMiddlewares[route])
+}
+
+// chain wraps the handler with the middlewares, the first one being the outermost.
+func chain(handler http.Handler, middlewares []Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// WithMiddleware adds middlewares wrapping every route of the server, the first one being the outermost.
+func WithMiddleware(middlewares ...Middleware) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.middlewares = append(server.middlewares, middlewares...)
+	})
+}
+
+// WithRouteMiddleware adds middlewares wrapping a single route, inside the middlewares of the server.
+func WithRouteMiddleware(route Route, middlewares ...Middleware) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		if server.routeMiddlewares == nil {
+			server.routeMiddlewares = make(map[Route][]Middleware)
+		}
+		server.routeMiddlewares[route] = append(server.routeMiddlewares[route], middlewares...)
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// recordMiddleware appends its name to the X-Middlewares header of the response.
+func recordMiddleware(name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Middlewares", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestServerHandler(t *testing.T) {
+	newAgent := func(name string, options ...ServerConfigOption) http.Handler {
+		card := mockAgentCard
+		card.Name = name
+		handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), newExecutor(), WithQueueManager(QueueManger{}))
+		h, err := NewHTTPHandler("/agents/"+name+"/card", "/agents/"+name+"/rpc", card, handler, options...)
+		require.NoError(t, err)
+		return h
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/agents/a/", newAgent("a",
+		WithMiddleware(recordMiddleware("logging"), recordMiddleware("recovery")),
+		WithRouteMiddleware(RouteAgentCard, recordMiddleware("card"), Headers(map[string]string{"Access-Control-Allow-Origin": "*"})),
+	))
+	mux.Handle("/agents/b/", newAgent("b"))
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	getCard := func(path string) (*http.Response, types.AgentCard) {
+		resp, err := http.Get(ts.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var card types.AgentCard
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&card))
+		return resp, card
+	}
+
+	resp, card := getCard("/agents/a/card")
+	assert.Equal(t, "a", card.Name)
+	assert.Equal(t, []string{"logging", "recovery", "card"}, resp.Header.Values("X-Middlewares"))
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+
+	resp, card = getCard("/agents/b/card")
+	assert.Equal(t, "b", card.Name)
+	assert.Empty(t, resp.Header.Values("X-Middlewares"), "middlewares are per server")
+
+	body, err := json.Marshal(types.JSONRPCRequest{
+		Id:     "1",
+		Method: types.MethodMessageStream,
+		Params: types.MessageSendParam{Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User}},
+	})
+	require.NoError(t, err)
+	resp, err = http.Post(ts.URL+"/agents/a/rpc", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"))
+	assert.Equal(t, []string{"logging", "recovery"}, resp.Header.Values("X-Middlewares"), "route middlewares wrap their route only")
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"), "no CORS header unless configured")
+
+	resp, err = http.Get(ts.URL + "/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
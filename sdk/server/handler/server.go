@@ -15,13 +15,21 @@
 package handler
 
 import (
+	"context"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/yeeaiclub/a2a-go/internal/errs"
 	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
 	"github.com/yeeaiclub/a2a-go/sdk/server"
+	"github.com/yeeaiclub/a2a-go/sdk/server/scheduler"
 	"github.com/yeeaiclub/a2a-go/sdk/types"
 )
 
@@ -33,13 +41,43 @@ const (
 
 // Server implements the main HTTP server for agent APIs, including JSON-RPC and streaming endpoints.
 type Server struct {
-	agentCardPath string          // Path for agent card metadata
-	card          types.AgentCard // Agent card metadata
-	handler       Handler         // Business logic handler
-	basePath      string          // Base path for API
-	readTimeout   time.Duration   // HTTP read timeout
-	writeTimeout  time.Duration   // HTTP write timeout
-	idleTimeout   time.Duration   // HTTP idle timeout
+	agentCardPath string            // Path for agent card metadata
+	cardProvider  AgentCardProvider // Provides the agent card metadata
+	cacheControl  string            // Cache-Control header of agent card responses
+	handler       Handler           // Business logic handler
+	basePath      string            // Base path for API
+	readTimeout   time.Duration     // HTTP read timeout
+	writeTimeout  time.Duration     // HTTP write timeout
+	idleTimeout   time.Duration     // HTTP idle timeout
+
+	authenticators map[string]auth.Authenticator // Authenticators by security scheme name
+
+	extendedCard     *types.AgentCard // Agent card served to authenticated callers
+	extendedCardPath string           // Path for the authenticated extended agent card
+
+	certFile  string         // Server certificate, enabling HTTPS
+	keyFile   string         // Server private key
+	clientCAs *x509.CertPool // Authorities of the client certificates, enabling mutual TLS
+
+	shutdownTimeout time.Duration // How long Start waits for running executors once its context is done
+	mu              sync.Mutex
+	httpServer      *http.Server  // Server launched by Start
+	draining        atomic.Bool   // Set by Shutdown, requests starting a task are rejected
+	closing         chan struct{} // Closed by Shutdown once executors are drained, ending open streams
+	closeOnce       sync.Once
+
+	middlewares      []Middleware           // Middlewares wrapping every route
+	routeMiddlewares map[Route][]Middleware // Middlewares wrapping a single route
+	cors             Middleware             // CORS policy, outside the other middlewares
+
+	maxRequestBytes       int64            // Size limit of request bodies
+	methodMaxRequestBytes map[string]int64 // Size limits of request bodies by method
+	maxRequestDepth       int              // Nesting limit of request bodies
+
+	rateLimiter   RateLimiter   // Limits the requests and running tasks of each principal
+	principalFunc PrincipalFunc // Attributes requests to principals, DefaultPrincipal if nil
+
+	cardURL func(ctx context.Context) (string, bool) // Rewrites the card URL of an agent mounted by a Router
 }
 
 // NewServer creates a new Server with the given configuration and options.
@@ -47,11 +85,18 @@ func NewServer(cardPath string, basePath string, card types.AgentCard, handler H
 	svc := &Server{
 		basePath:      basePath,
 		agentCardPath: cardPath,
-		card:          card,
+		cardProvider:  StaticAgentCard(card),
+		cacheControl:  defaultCardCacheControl,
 		handler:       handler,
 		readTimeout:   defaultReadTimeout,
 		writeTimeout:  defaultWriteTimeout,
 		idleTimeout:   defaultIdleTimeout,
+
+		extendedCardPath: types.ExtendedAgentCardPath,
+		shutdownTimeout:  defaultShutdownTimeout,
+		maxRequestBytes:  defaultMaxRequestBytes,
+		maxRequestDepth:  defaultMaxRequestDepth,
+		closing:          make(chan struct{}),
 	}
 	for _, opt := range options {
 		opt.Option(svc)
@@ -59,21 +104,60 @@ func NewServer(cardPath string, basePath string, card types.AgentCard, handler H
 	return svc
 }
 
-// Start launches the HTTP server on the specified port.
-func (s *Server) Start(port int) error {
-	mux := http.NewServeMux()
-	mux.HandleFunc(s.agentCardPath, s.handleGetAgentCard)
-	mux.Handle(s.basePath, s)
+// Start launches the HTTP server on the specified port, serving HTTPS when WithTLS is set,
+// and blocks until the server is shut down. When the context is done, the server is shut
+// down as by Shutdown, waiting for the running executors for the timeout set by
+// WithShutdownTimeout. It refuses to start if the agent card or the extended agent card
+// has errors, and returns nil once the server is shut down gracefully.
+func (s *Server) Start(ctx context.Context, port int) error {
+	if err := s.Validate(ctx); err != nil {
+		return err
+	}
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
 	svc := &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      mux,
+		Handler:      s.httpHandler(),
+		TLSConfig:    tlsConfig,
 		ReadTimeout:  s.readTimeout,
 		WriteTimeout: s.writeTimeout,
 		IdleTimeout:  s.idleTimeout,
 	}
-	log.Infof("Starting HTTP svc on :%d with ReadTimeout=%v, WriteTimeout=%v, IdleTimeout=%v",
-		port, s.readTimeout, s.writeTimeout, s.idleTimeout)
-	return svc.ListenAndServe()
+	log.Infof("Starting HTTP svc on :%d with ReadTimeout=%v, WriteTimeout=%v, IdleTimeout=%v, TLS=%v, MutualTLS=%v",
+		port, s.readTimeout, s.writeTimeout, s.idleTimeout, tlsConfig != nil, s.clientCAs != nil)
+	return s.serveUntilDone(ctx, svc, func() error {
+		if tlsConfig != nil {
+			return svc.ListenAndServeTLS("", "")
+		}
+		return svc.ListenAndServe()
+	})
+}
+
+// Validate validates the current agent card and the extended agent card. Warnings are logged,
+// and a *types.ValidationError is returned if one of the cards has errors.
+func (s *Server) Validate(ctx context.Context) error {
+	card, err := s.agentCard(ctx)
+	if err != nil {
+		return err
+	}
+	cards := map[string]types.AgentCard{"agent card": card}
+	if s.extendedCard != nil {
+		cards["extended agent card"] = *s.extendedCard
+	}
+	for name, card := range cards {
+		diagnostics := card.Validate()
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Severity == types.SeverityWarning {
+				log.Warnf("%s: %s", name, diagnostic)
+			}
+		}
+		if err := diagnostics.Err(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
 }
 
 // handleGetAgentCard handles GET requests for the agent card metadata.
@@ -83,8 +167,87 @@ func (s *Server) handleGetAgentCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	card, err := s.agentCard(r.Context())
+	if err != nil {
+		log.Errorf("handleGetAgentCard | %v", err)
+		http.Error(w, "Agent card unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	body, err := json.Marshal(card)
+	if err != nil {
+		s.sendError(w, "", types.JSONParseError(err))
+		return
+	}
+
+	etag := cardETag(body)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", s.cacheControl)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	if err := json.NewEncoder(w).Encode(s.card); err != nil {
+	_, _ = w.Write(append(body, '\n'))
+}
+
+// agentCard returns the current agent card of the provider.
+func (s *Server) agentCard(ctx context.Context) (types.AgentCard, error) {
+	card, err := s.cardProvider.AgentCard(ctx)
+	if err != nil {
+		return card, fmt.Errorf("failed to get agent card: %w", err)
+	}
+	if s.extendedCard != nil {
+		card.SupportsAuthenticatedExtendedCard = true
+	}
+	s.rewriteURL(ctx, &card)
+	return card, nil
+}
+
+// rewriteURL sets the URL the agent is mounted at on the card, if it is mounted by a Router.
+func (s *Server) rewriteURL(ctx context.Context, card *types.AgentCard) {
+	if s.cardURL == nil {
+		return
+	}
+	if url, ok := s.cardURL(ctx); ok {
+		card.URL = url
+	}
+}
+
+// handleGetExtendedAgentCard handles GET requests for the agent card served to authenticated callers.
+// The caller must satisfy the security requirement of the public card.
+func (s *Server) handleGetExtendedAgentCard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.extendedCard == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	card, err := s.agentCard(r.Context())
+	if err != nil {
+		log.Errorf("handleGetExtendedAgentCard | %v", err)
+		http.Error(w, "Agent card unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if !s.limitClient(w, r, card) {
+		return
+	}
+	user, err := s.authenticate(r, card)
+	if err == nil && !user.IsAuthenticated() {
+		err = auth.ErrMissingCredentials
+	}
+	if err != nil {
+		s.sendUnauthorized(w, card, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "private")
+	extended := *s.extendedCard
+	s.rewriteURL(r.Context(), &extended)
+	if err := json.NewEncoder(w).Encode(extended); err != nil {
 		s.sendError(w, "", types.JSONParseError(err))
 		return
 	}
@@ -97,16 +260,44 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var request types.JSONRPCRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		s.sendError(w, request.Id, types.JSONParseError(err))
+	card, err := s.agentCard(r.Context())
+	if err != nil {
+		log.Errorf("ServeHTTP | %v", err)
+		s.sendError(w, "", types.InternalError())
+		return
+	}
+	if !s.limitClient(w, r, card) {
+		return
+	}
+	user, err := s.authenticate(r, card)
+	if err != nil {
+		s.sendUnauthorized(w, card, err)
 		return
 	}
 
+	request, rpcErr := s.decodeRequest(w, r)
+	if rpcErr != nil {
+		s.sendError(w, request.Id, rpcErr)
+		return
+	}
+	r, release, ok := s.limit(w, r, card, user, &request)
+	if !ok {
+		return
+	}
+	defer release()
+
 	// Create CallContext with the HTTP request
 	callCtx := server.NewCallContextWithRequest(r)
-	// Ensure the context is released back to the pool when the request is done
-	defer callCtx.Release()
+	// Cancel the context when the request is done. It is not released back to the pool,
+	// since the executors and stream consumers of the request may still hold it.
+	defer callCtx.Cancel()
+	callCtx.SetUser(user)
+	callCtx.SetSecurityConfig(card.Security, card.SecuritySchemes)
+
+	if s.shuttingDown() && (request.Method == types.MethodMessageSend || request.Method == types.MethodMessageStream) {
+		s.sendError(w, request.Id, types.ShuttingDownError())
+		return
+	}
 
 	switch request.Method {
 	case types.MethodMessageSend:
@@ -128,9 +319,9 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (s *Server) handleMessageSend(ctx *server.CallContext, w http.ResponseWriter, request *types.JSONRPCRequest, id string) {
+func (s *Server) handleMessageSend(ctx *server.CallContext, w http.ResponseWriter, request *rpcRequest, id string) {
 	log.Infof("handleMessageSend called | id=%s, method=%s", id, request.Method)
-	params, err := types.MapTo[types.MessageSendParam](request.Params)
+	params, err := types.UnmarshalParams[types.MessageSendParam](request.Params)
 	if err != nil {
 		s.sendError(w, id, types.JSONParseError(err))
 		return
@@ -139,15 +330,15 @@ func (s *Server) handleMessageSend(ctx *server.CallContext, w http.ResponseWrite
 	event, err := s.handler.OnMessageSend(ctx, params)
 	if err != nil {
 		log.Errorf("handleMessageSend | onMessageSend | %v", err)
-		s.sendError(w, id, types.InternalError())
+		s.sendError(w, id, toJSONRPCError(err))
 		return
 	}
 	s.sendResponse(w, id, event)
 }
 
-func (s *Server) handleMessageSendStream(ctx *server.CallContext, w http.ResponseWriter, request *types.JSONRPCRequest, id string) {
+func (s *Server) handleMessageSendStream(ctx *server.CallContext, w http.ResponseWriter, request *rpcRequest, id string) {
 	log.Infof("handleMessageSendStream called | id=%s, method=%s", id, request.Method)
-	params, err := types.MapTo[types.MessageSendParam](request.Params)
+	params, err := types.UnmarshalParams[types.MessageSendParam](request.Params)
 	if err != nil {
 		s.sendError(w, id, types.JSONParseError(err))
 		return
@@ -156,7 +347,6 @@ func (s *Server) handleMessageSendStream(ctx *server.CallContext, w http.Respons
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -171,6 +361,10 @@ func (s *Server) handleMessageSendStream(ctx *server.CallContext, w http.Respons
 		select {
 		case <-ctx.Done():
 			return
+		case <-s.closing:
+			_ = encoder.Encode(types.JSONRPCErrorResponse(id, types.ShuttingDownError()))
+			flusher.Flush()
+			return
 		case ev, o := <-events:
 			if !o {
 				return
@@ -180,7 +374,11 @@ func (s *Server) handleMessageSendStream(ctx *server.CallContext, w http.Respons
 				_ = ev.EncodeJSONRPC(encoder, id)
 				flusher.Flush()
 			case types.EventError:
-				_ = ev.EncodeJSONRPC(encoder, id)
+				if s.interruptedByShutdown(ev) {
+					_ = encoder.Encode(types.JSONRPCErrorResponse(id, types.ShuttingDownError()))
+				} else {
+					_ = ev.EncodeJSONRPC(encoder, id)
+				}
 				flusher.Flush()
 				return
 			case types.EventDone:
@@ -188,6 +386,10 @@ func (s *Server) handleMessageSendStream(ctx *server.CallContext, w http.Respons
 				flusher.Flush()
 				return
 			case types.EventClosed:
+				if s.interruptedByShutdown(ev) {
+					_ = encoder.Encode(types.JSONRPCErrorResponse(id, types.ShuttingDownError()))
+					flusher.Flush()
+				}
 				return
 			default:
 			}
@@ -196,9 +398,9 @@ func (s *Server) handleMessageSendStream(ctx *server.CallContext, w http.Respons
 }
 
 // handleGetTask handles the tasks/get JSON-RPC method.
-func (s *Server) handleGetTask(ctx *server.CallContext, w http.ResponseWriter, request *types.JSONRPCRequest, id string) {
+func (s *Server) handleGetTask(ctx *server.CallContext, w http.ResponseWriter, request *rpcRequest, id string) {
 	log.Infof("handleGetTask called | id=%s, method=%s", id, request.Method)
-	params, err := types.MapTo[types.TaskQueryParams](request.Params)
+	params, err := types.UnmarshalParams[types.TaskQueryParams](request.Params)
 	if err != nil {
 		s.sendError(w, id, types.JSONParseError(err))
 		return
@@ -206,16 +408,16 @@ func (s *Server) handleGetTask(ctx *server.CallContext, w http.ResponseWriter, r
 	event, err := s.handler.OnGetTask(ctx, params)
 	if err != nil {
 		log.Errorf("handleGetTask | onGetTask| %v", err)
-		s.sendError(w, id, types.InternalError())
+		s.sendError(w, id, toJSONRPCError(err))
 		return
 	}
 	s.sendResponse(w, id, event)
 }
 
 // handleCancelTask handles the tasks/cancel JSON-RPC method.
-func (s *Server) handleCancelTask(ctx *server.CallContext, w http.ResponseWriter, request *types.JSONRPCRequest, id string) {
+func (s *Server) handleCancelTask(ctx *server.CallContext, w http.ResponseWriter, request *rpcRequest, id string) {
 	log.Infof("handleCancelTask called | id=%s, method=%s", id, request.Method)
-	params, err := types.MapTo[types.TaskIdParams](request.Params)
+	params, err := types.UnmarshalParams[types.TaskIdParams](request.Params)
 	if err != nil {
 		s.sendError(w, id, types.JSONParseError(err))
 		return
@@ -224,16 +426,16 @@ func (s *Server) handleCancelTask(ctx *server.CallContext, w http.ResponseWriter
 	event, err := s.handler.OnCancelTask(ctx, params)
 	if err != nil {
 		log.Errorf("handleCancelTaskk | onCancelTask | %v", err)
-		s.sendError(w, id, types.InternalError())
+		s.sendError(w, id, toJSONRPCError(err))
 		return
 	}
 	s.sendResponse(w, id, event)
 }
 
 // handleSetTaskPushNotificationConfig handles the tasks/pushNotificationConfig/set JSON-RPC method.
-func (s *Server) handleSetTaskPushNotificationConfig(ctx *server.CallContext, w http.ResponseWriter, request *types.JSONRPCRequest, id string) {
+func (s *Server) handleSetTaskPushNotificationConfig(ctx *server.CallContext, w http.ResponseWriter, request *rpcRequest, id string) {
 	log.Infof("handleSetTaskPushNotificationConfig called | id=%s, method=%s", id, request.Method)
-	params, err := types.MapTo[types.TaskPushNotificationConfig](request.Params)
+	params, err := types.UnmarshalParams[types.TaskPushNotificationConfig](request.Params)
 	if err != nil {
 		s.sendError(w, id, types.JSONParseError(err))
 		return
@@ -242,16 +444,16 @@ func (s *Server) handleSetTaskPushNotificationConfig(ctx *server.CallContext, w
 	event, err := s.handler.OnSetTaskPushNotificationConfig(ctx, params)
 	if err != nil {
 		log.Errorf("handleSetTaskPushNotificationConfig | OnSetTaskPushNotificationConfig | %v", err)
-		s.sendError(w, id, types.InternalError())
+		s.sendError(w, id, toJSONRPCError(err))
 		return
 	}
 	s.sendResponse(w, id, event)
 }
 
 // handleGetTaskPushNotificationConfig handles the tasks/pushNotificationConfig/get JSON-RPC method.
-func (s *Server) handleGetTaskPushNotificationConfig(ctx *server.CallContext, w http.ResponseWriter, request *types.JSONRPCRequest, id string) {
+func (s *Server) handleGetTaskPushNotificationConfig(ctx *server.CallContext, w http.ResponseWriter, request *rpcRequest, id string) {
 	log.Infof("handleGetTaskPushNotificationConfig called | id=%s, method=%s", id, request.Method)
-	params, err := types.MapTo[types.TaskIdParams](request.Params)
+	params, err := types.UnmarshalParams[types.TaskIdParams](request.Params)
 	if err != nil {
 		s.sendError(w, id, types.JSONParseError(err))
 		return
@@ -260,16 +462,16 @@ func (s *Server) handleGetTaskPushNotificationConfig(ctx *server.CallContext, w
 	event, err := s.handler.OnGetTaskPushNotificationConfig(ctx, params)
 	if err != nil {
 		log.Errorf("handleGetTaskPushNotificationConfig | OnGetTaskPushNotificationConfig | %v", err)
-		s.sendError(w, id, types.InternalError())
+		s.sendError(w, id, toJSONRPCError(err))
 		return
 	}
 	s.sendResponse(w, id, event)
 }
 
 // handleResubscribeToTask handles the tasks/resubscribe JSON-RPC method with SSE.
-func (s *Server) handleResubscribeToTask(ctx *server.CallContext, w http.ResponseWriter, request *types.JSONRPCRequest, id string) {
+func (s *Server) handleResubscribeToTask(ctx *server.CallContext, w http.ResponseWriter, request *rpcRequest, id string) {
 	log.Infof("handleResubscribeToTask called | id=%s, method=%s", id, request.Method)
-	params, err := types.MapTo[types.TaskIdParams](request.Params)
+	params, err := types.UnmarshalParams[types.TaskIdParams](request.Params)
 	if err != nil {
 		s.sendError(w, id, types.InternalError())
 		return
@@ -278,7 +480,6 @@ func (s *Server) handleResubscribeToTask(ctx *server.CallContext, w http.Respons
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -293,6 +494,10 @@ func (s *Server) handleResubscribeToTask(ctx *server.CallContext, w http.Respons
 		select {
 		case <-ctx.Done():
 			return
+		case <-s.closing:
+			_ = encoder.Encode(types.JSONRPCErrorResponse(id, types.ShuttingDownError()))
+			flusher.Flush()
+			return
 		case ev, o := <-events:
 			if !o {
 				return
@@ -302,7 +507,11 @@ func (s *Server) handleResubscribeToTask(ctx *server.CallContext, w http.Respons
 				_ = ev.EncodeJSONRPC(encoder, id)
 				flusher.Flush()
 			case types.EventError:
-				_ = ev.EncodeJSONRPC(encoder, id)
+				if s.interruptedByShutdown(ev) {
+					_ = encoder.Encode(types.JSONRPCErrorResponse(id, types.ShuttingDownError()))
+				} else {
+					_ = ev.EncodeJSONRPC(encoder, id)
+				}
 				flusher.Flush()
 				return
 			case types.EventDone:
@@ -310,6 +519,10 @@ func (s *Server) handleResubscribeToTask(ctx *server.CallContext, w http.Respons
 				flusher.Flush()
 				return
 			case types.EventClosed:
+				if s.interruptedByShutdown(ev) {
+					_ = encoder.Encode(types.JSONRPCErrorResponse(id, types.ShuttingDownError()))
+					flusher.Flush()
+				}
 				return
 			default:
 			}
@@ -317,6 +530,20 @@ func (s *Server) handleResubscribeToTask(ctx *server.CallContext, w http.Respons
 	}
 }
 
+// toJSONRPCError converts an error of the handler to the JSON-RPC error sent to the client.
+func toJSONRPCError(err error) *types.JSONRPCError {
+	if errors.Is(err, errs.ErrPermissionDenied) {
+		return types.PermissionDeniedError()
+	}
+	if errors.Is(err, errs.ErrShuttingDown) {
+		return types.ShuttingDownError()
+	}
+	if errors.Is(err, scheduler.ErrQueueFull) {
+		return types.TooManyTasksError()
+	}
+	return types.InternalError()
+}
+
 // sendError writes a JSON-RPC error response.
 func (s *Server) sendError(w http.ResponseWriter, id string, err *types.JSONRPCError) {
 	response := types.JSONRPCResponse{
@@ -371,3 +598,33 @@ func WithIdleTimeout(idleTimeout time.Duration) ServerConfigOption {
 		server.idleTimeout = idleTimeout
 	})
 }
+
+// WithExtendedAgentCard sets the agent card served to authenticated callers, usually the
+// public card with additional skills. The public card then advertises that it supports it.
+func WithExtendedAgentCard(card types.AgentCard) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.extendedCard = &card
+	})
+}
+
+// WithExtendedAgentCardPath sets the path of the authenticated extended agent card.
+// By default, it is types.ExtendedAgentCardPath.
+func WithExtendedAgentCardPath(path string) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.extendedCardPath = path
+	})
+}
+
+// WithAgentCardProvider sets the provider of the agent card, which replaces the card given to NewServer.
+func WithAgentCardProvider(provider AgentCardProvider) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.cardProvider = provider
+	})
+}
+
+// WithAgentCardCacheControl sets the Cache-Control header of agent card responses.
+func WithAgentCardCacheControl(cacheControl string) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.cacheControl = cacheControl
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/yeeaiclub/a2a-go/internal/logger"
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// MetadataTimeout is the metadata key of the execution timeout requested for a message, a number
+// of seconds or a duration string such as "90s". It can only shorten the configured timeout.
+const MetadataTimeout = "timeout"
+
+// timeout returns the execution timeout of the task, none if zero. The skill is named by the
+// client, so the timeout of the skill and the requested timeout can only shorten the timeout
+// of the handler, never extend or disable it.
+func (d *DefaultHandler) timeout(params types.MessageSendParam, skill string) time.Duration {
+	timeout := d.executionTimeout
+	if skillTimeout := d.skillTimeouts[skill]; skillTimeout > 0 && (timeout <= 0 || skillTimeout < timeout) {
+		timeout = skillTimeout
+	}
+	if requested, ok := requestedTimeout(params); ok && (timeout <= 0 || requested < timeout) {
+		timeout = requested
+	}
+	return timeout
+}
+
+// requestedTimeout returns the timeout of the metadata of the message, else of the request.
+func requestedTimeout(params types.MessageSendParam) (time.Duration, bool) {
+	metadata := []map[string]any{params.Metadata}
+	if params.Message != nil {
+		metadata = []map[string]any{params.Message.Metadata, params.Metadata}
+	}
+	for _, m := range metadata {
+		if timeout, ok := toTimeout(m[MetadataTimeout]); ok {
+			return timeout, true
+		}
+	}
+	return 0, false
+}
+
+func toTimeout(value any) (time.Duration, bool) {
+	var timeout time.Duration
+	switch v := value.(type) {
+	case float64:
+		timeout = time.Duration(v * float64(time.Second))
+	case string:
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+			timeout = time.Duration(seconds * float64(time.Second))
+		} else if duration, err := time.ParseDuration(v); err == nil {
+			timeout = duration
+		}
+	}
+	return timeout, timeout > 0
+}
+
+// withDeadline returns the context of the executor, canceled once the timeout passes after the
+// task was marked FAILED and the executor asked to cancel it. The returned function must be
+// called once the executor returns, before the queue is closed.
+func (d *DefaultHandler) withDeadline(ctx context.Context, reqCtx *execution.RequestContext, queue *event.Queue, timeout time.Duration) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	expired := make(chan struct{})
+	go func() {
+		defer close(expired)
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			d.expire(ctx, reqCtx, queue, timeout)
+			cancel()
+		}
+	}()
+	return ctx, func() {
+		close(done)
+		<-expired
+		cancel()
+	}
+}
+
+// expire fails the task whose deadline passed, then asks the executor to cancel it.
+func (d *DefaultHandler) expire(ctx context.Context, reqCtx *execution.RequestContext, queue *event.Queue, timeout time.Duration) {
+	log.Warnf("task %s timed out after %v", reqCtx.TaskId, timeout)
+	d.failTask(ctx, reqCtx, queue, fmt.Sprintf("The task timed out after %v", timeout))
+
+	defer d.recoverCancel(ctx, reqCtx, queue)
+	if err := d.executor.Cancel(ctx, reqCtx, queue); err != nil {
+		log.Warnf("failed to cancel task %s after its timeout: %v", reqCtx.TaskId, err)
+	}
+}
+
+// WithExecutionTimeout sets how long an agent executor may run, from the time it starts.
+// Once the timeout passes, the task is marked FAILED, the executor is asked to cancel it
+// and its context is canceled. By default, executors have no timeout.
+func WithExecutionTimeout(timeout time.Duration) HandlerOption {
+	return HandlerOptionFunc(func(d *DefaultHandler) {
+		d.executionTimeout = timeout
+	})
+}
+
+// WithSkillExecutionTimeout sets the execution timeout of the tasks of a skill, taken from
+// the scheduler.MetadataSkill metadata of their message, see WithExecutionTimeout. Since the
+// client names the skill, the timeout only applies if it is shorter than the one set by
+// WithExecutionTimeout; zero is ignored.
+func WithSkillExecutionTimeout(skill string, timeout time.Duration) HandlerOption {
+	return HandlerOptionFunc(func(d *DefaultHandler) {
+		if d.skillTimeouts == nil {
+			d.skillTimeouts = make(map[string]time.Duration)
+		}
+		d.skillTimeouts[skill] = timeout
+	})
+}
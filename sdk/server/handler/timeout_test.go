@@ -0,0 +1,156 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server"
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/execution"
+	"github.com/yeeaiclub/a2a-go/sdk/server/scheduler"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// hangingExecutor works on the task until its context is canceled.
+type hangingExecutor struct {
+	canceled atomic.Int32
+}
+
+func (e *hangingExecutor) Execute(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	updater.NewTaskUpdater(queue, requestContext.TaskId, requestContext.ContextId).StartWork()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (e *hangingExecutor) Cancel(ctx context.Context, requestContext *execution.RequestContext, queue *event.Queue) error {
+	e.canceled.Add(1)
+	return nil
+}
+
+func TestExecutionTimeout(t *testing.T) {
+	testcases := []struct {
+		name     string
+		options  []HandlerOption
+		metadata map[string]any
+		want     string
+	}{
+		{name: "handler timeout", options: []HandlerOption{WithExecutionTimeout(50 * time.Millisecond)}, want: "The task timed out after 50ms"},
+		{
+			name:     "skill timeout",
+			options:  []HandlerOption{WithExecutionTimeout(time.Hour), WithSkillExecutionTimeout("search", 40*time.Millisecond)},
+			metadata: map[string]any{scheduler.MetadataSkill: "search"},
+			want:     "The task timed out after 40ms",
+		},
+		{
+			name:     "requested timeout",
+			options:  []HandlerOption{WithExecutionTimeout(time.Hour)},
+			metadata: map[string]any{"timeout": "30ms"},
+			want:     "The task timed out after 30ms",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := tasks.NewInMemoryTaskStore()
+			executor := &hangingExecutor{}
+			handler := NewDefaultHandler(store, executor, append(tc.options, WithQueueManager(QueueManger{}))...)
+
+			var last types.StreamEvent
+			for ev := range handler.OnMessageSendStream(server.NewCallContext(context.Background()), types.MessageSendParam{
+				Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User, Metadata: tc.metadata},
+			}) {
+				last = ev
+			}
+			require.Equal(t, types.EventDone, last.Type, "the stream ends with a final event")
+			update, ok := last.Event.(*types.TaskStatusUpdateEvent)
+			require.True(t, ok)
+			assert.Equal(t, types.FAILED, update.Status.State)
+			require.NotNil(t, update.Status.Message)
+			assert.Equal(t, tc.want, update.Status.Message.Parts[0].(*types.TextPart).Text)
+
+			require.NoError(t, handler.Shutdown(context.Background()), "the executor context was canceled")
+			assert.Equal(t, int32(1), executor.canceled.Load())
+			task, err := store.Get(context.Background(), "1")
+			require.NoError(t, err)
+			assert.Equal(t, types.FAILED, task.Status.State)
+		})
+	}
+}
+
+func TestExecutionOutlivesRequest(t *testing.T) {
+	store := tasks.NewInMemoryTaskStore()
+	executor := &gatedExecutor{release: make(chan struct{})}
+	handler := NewDefaultHandler(store, executor, WithQueueManager(QueueManger{}))
+
+	callCtx := server.NewCallContext(context.Background())
+	events := handler.OnMessageSendStream(callCtx, types.MessageSendParam{
+		Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User},
+	})
+	<-events
+	callCtx.Cancel()
+	for range events {
+	}
+
+	close(executor.release)
+	require.NoError(t, handler.Shutdown(context.Background()))
+	assert.Equal(t, 0, len(handler.running), "the executor returned")
+}
+
+func TestTimeoutResolution(t *testing.T) {
+	handler := NewDefaultHandler(nil, nil, WithExecutionTimeout(time.Minute),
+		WithSkillExecutionTimeout("report", time.Hour),
+		WithSkillExecutionTimeout("lookup", 10*time.Second),
+		WithSkillExecutionTimeout("unbounded", 0))
+	testcases := []struct {
+		name   string
+		params types.MessageSendParam
+		skill  string
+		want   time.Duration
+	}{
+		{name: "handler timeout", want: time.Minute},
+		{name: "shorter skill timeout", skill: "lookup", want: 10 * time.Second},
+		{name: "longer skill timeout", skill: "report", want: time.Minute},
+		{name: "skill cannot disable", skill: "unbounded", want: time.Minute},
+		{name: "requested shorter than skill", skill: "lookup", params: types.MessageSendParam{Metadata: map[string]any{"timeout": float64(5)}}, want: 5 * time.Second},
+		{name: "requested seconds", params: types.MessageSendParam{Metadata: map[string]any{"timeout": float64(5)}}, want: 5 * time.Second},
+		{name: "cannot extend", params: types.MessageSendParam{Metadata: map[string]any{"timeout": "2h"}}, want: time.Minute},
+		{
+			name: "message metadata takes precedence",
+			params: types.MessageSendParam{
+				Metadata: map[string]any{"timeout": "10s"},
+				Message:  &types.Message{Metadata: map[string]any{"timeout": "20"}},
+			},
+			want: 20 * time.Second,
+		},
+		{name: "invalid timeout", params: types.MessageSendParam{Metadata: map[string]any{"timeout": "soon"}}, want: time.Minute},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, handler.timeout(tc.params, tc.skill))
+		})
+	}
+
+	handler = NewDefaultHandler(nil, nil, WithSkillExecutionTimeout("report", time.Hour))
+	assert.Equal(t, time.Hour, handler.timeout(types.MessageSendParam{}, "report"), "skill timeout without handler timeout")
+	assert.Zero(t, handler.timeout(types.MessageSendParam{}, ""))
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// WithTLS serves HTTPS with the certificate and key read from the given PEM files.
+func WithTLS(certFile, keyFile string) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.certFile = certFile
+		server.keyFile = keyFile
+	})
+}
+
+// WithMutualTLS requires clients to present a certificate signed by one of the given
+// certificate authorities, verified during the TLS handshake. It needs WithTLS. Register
+// auth.NewCertificateAuthenticator for the mutualTLS scheme of the card to map the
+// certificates to users.
+func WithMutualTLS(clientCAs *x509.CertPool) ServerConfigOption {
+	return ServerConfigOptionFunc(func(server *Server) {
+		server.clientCAs = clientCAs
+	})
+}
+
+// tlsConfig returns the TLS configuration of the server, or nil when it serves plain HTTP.
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	if s.certFile == "" && s.keyFile == "" {
+		if s.clientCAs != nil {
+			return nil, fmt.Errorf("mutual TLS requires a server certificate")
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if s.clientCAs != nil {
+		config.ClientCAs = s.clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
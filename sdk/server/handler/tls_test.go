@@ -0,0 +1,156 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/auth"
+	"github.com/yeeaiclub/a2a-go/sdk/client"
+	"github.com/yeeaiclub/a2a-go/sdk/client/card"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// issue returns a certificate signed by the authority, as a tls.Certificate and PEM blocks.
+func (ca *testCA) issue(t *testing.T, template *x509.Certificate) (tls.Certificate, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	return cert, certPEM, keyPEM
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	_, serverCert, serverKey := ca.issue(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "server"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	clientCert, _, _ := ca.issue(t, &x509.Certificate{
+		DNSNames:    []string{"agent-b.example.com"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certFile, serverCert, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, serverKey, 0o600))
+
+	agentCard := mockAgentCard
+	agentCard.Security = types.SecurityRequirement{{"mtls": nil}}
+	agentCard.SecuritySchemes = map[string]types.SecurityScheme{"mtls": types.MutualTLSSecurityScheme{}}
+	executor := &userExecutor{}
+	handler := NewDefaultHandler(tasks.NewInMemoryTaskStore(), executor, WithQueueManager(QueueManger{}))
+	server := NewServer("/card", "/", agentCard, handler,
+		WithTLS(certFile, keyFile),
+		WithMutualTLS(ca.pool),
+		WithAuthenticator("mtls", auth.NewCertificateAuthenticator(nil)),
+	)
+
+	tlsConfig, err := server.tlsConfig()
+	require.NoError(t, err)
+	h, err := server.Handler()
+	require.NoError(t, err)
+	ts := httptest.NewUnstartedServer(h)
+	ts.TLS = tlsConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	noCertificate := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.pool}}}
+	_, err = card.NewA2ACardResolver(noCertificate, ts.URL,
+		card.WithAgentCardPath("/card")).GetAgentCard(t.Context())
+	assert.Error(t, err, "the handshake fails without a client certificate")
+
+	resolved, err := card.NewA2ACardResolver(http.DefaultClient, ts.URL,
+		card.WithAgentCardPath("/card"), card.WithClientCertificate(clientCert, ca.pool)).GetAgentCard(t.Context())
+	require.NoError(t, err)
+	require.True(t, resolved.RequiresMutualTLS())
+
+	a2aClient := client.NewClient(http.DefaultClient, ts.URL, client.WithAgentCard(resolved),
+		client.WithClientCertificate(clientCert, ca.pool))
+	resp, err := a2aClient.SendMessage(types.MessageSendParam{
+		Message: &types.Message{TaskID: "1", ContextID: "2", Role: types.User},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp.Error)
+	require.NotNil(t, executor.user)
+	assert.Equal(t, "agent-b.example.com", executor.user.UserName())
+}
+
+func TestTLSConfig(t *testing.T) {
+	server := NewServer("/card", "/", mockAgentCard, nil, WithMutualTLS(x509.NewCertPool()))
+	_, err := server.tlsConfig()
+	assert.Error(t, err, "mutual TLS requires a server certificate")
+
+	server = NewServer("/card", "/", mockAgentCard, nil)
+	config, err := server.tlsConfig()
+	require.NoError(t, err)
+	assert.Nil(t, config)
+}
@@ -0,0 +1,294 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheduler schedules the agent executors run by the request handler.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/server/tasks/updater"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+const (
+	// MetadataPriority is the metadata key of the priority of a message, a number or a numeric
+	// string. Tasks with a higher priority are picked up first, the default priority is 0.
+	MetadataPriority = "priority"
+	// MetadataSkill is the metadata key of the id of the skill a message is for. It selects the
+	// concurrency limits set by WithSkillLimit, and the handler's skill timeouts and authorization rules.
+	// It is sent by the client, which may name any skill or none, so it is not a security boundary.
+	MetadataSkill = "skill"
+)
+
+// ErrQueueFull is returned by Schedule when the pending queue reached the size set by WithMaxQueue.
+var ErrQueueFull = errors.New("scheduler queue is full")
+
+// Scheduler runs the jobs of the handler, each running an agent executor.
+type Scheduler interface {
+	// Schedule runs the job now or later. The job is always run once, even if its context is
+	// done before it is picked up, so that it can release its resources, unless an error is returned.
+	Schedule(ctx context.Context, job Job) error
+}
+
+// Job is an agent executor run for a task.
+type Job struct {
+	TaskId    string
+	ContextId string
+	Skill     string       // Skill the task is for, see MetadataSkill
+	Priority  int          // See MetadataPriority
+	Queue     *event.Queue // Event queue of the task, receiving the SUBMITTED status while the job waits
+	Run       func(ctx context.Context)
+}
+
+// NewJob returns the job running the task of the message, with the skill and priority of its metadata.
+// The metadata of the message takes precedence over the metadata of the request.
+func NewJob(taskId, contextId string, params types.MessageSendParam, queue *event.Queue, run func(ctx context.Context)) Job {
+	job := Job{TaskId: taskId, ContextId: contextId, Queue: queue, Run: run}
+	metadata := []map[string]any{params.Metadata}
+	if params.Message != nil {
+		metadata = append(metadata, params.Message.Metadata)
+	}
+	for _, m := range metadata {
+		if skill, ok := m[MetadataSkill].(string); ok {
+			job.Skill = skill
+		}
+		if priority, ok := toPriority(m[MetadataPriority]); ok {
+			job.Priority = priority
+		}
+	}
+	return job
+}
+
+func toPriority(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	case string:
+		priority, err := strconv.Atoi(v)
+		return priority, err == nil
+	}
+	return 0, false
+}
+
+// JobInfo describes a job queued or running in a Pool.
+type JobInfo struct {
+	TaskId    string
+	ContextId string
+	Skill     string
+	Priority  int
+	QueuedAt  time.Time
+	StartedAt time.Time // Zero while the job is queued
+}
+
+// Pool is a Scheduler running a bounded number of jobs at once. The other jobs wait in a
+// queue ordered by priority, then by arrival, and their tasks report the SUBMITTED state
+// until they are picked up. Jobs of a skill with a concurrency limit wait for that skill.
+type Pool struct {
+	workers     int
+	maxQueue    int
+	skillLimits map[string]int
+	now         func() time.Time
+
+	mu      sync.Mutex
+	pending []*entry // Queued jobs, by decreasing priority then arrival
+	running map[*entry]struct{}
+	skills  map[string]int // Running jobs by skill
+}
+
+type entry struct {
+	job     Job
+	ctx     context.Context
+	info    JobInfo
+	stopCtx func() bool // Stops the removal of the job once its context is done
+}
+
+// PoolOption configures a Pool.
+type PoolOption interface {
+	Option(p *Pool)
+}
+
+// PoolOptionFunc is a function type for PoolOption.
+type PoolOptionFunc func(p *Pool)
+
+func (fn PoolOptionFunc) Option(p *Pool) {
+	fn(p)
+}
+
+// NewPool creates a Pool running at most workers jobs at once.
+func NewPool(workers int, options ...PoolOption) *Pool {
+	p := &Pool{
+		workers:     max(workers, 1),
+		skillLimits: make(map[string]int),
+		now:         time.Now,
+		running:     make(map[*entry]struct{}),
+		skills:      make(map[string]int),
+	}
+	for _, opt := range options {
+		opt.Option(p)
+	}
+	return p
+}
+
+// Schedule runs the job if a worker and its skill are available, otherwise queues it and sets
+// its task to SUBMITTED. A queued job whose context is done is dequeued and run right away,
+// with the done context.
+func (p *Pool) Schedule(ctx context.Context, job Job) error {
+	if job.Run == nil {
+		return fmt.Errorf("job of task %s has nothing to run", job.TaskId)
+	}
+	e := &entry{job: job, ctx: ctx, info: JobInfo{
+		TaskId:    job.TaskId,
+		ContextId: job.ContextId,
+		Skill:     job.Skill,
+		Priority:  job.Priority,
+		QueuedAt:  p.now(),
+	}}
+
+	p.mu.Lock()
+	if p.maxQueue > 0 && len(p.pending) >= p.maxQueue {
+		p.mu.Unlock()
+		return ErrQueueFull
+	}
+	p.enqueue(e)
+	started := p.dispatch()
+	if !slices.Contains(started, e) {
+		// Enqueued under the lock, so that it precedes the events of the executor.
+		if job.Queue != nil {
+			updater.NewTaskUpdater(job.Queue, job.TaskId, job.ContextId).Submit()
+		}
+		e.stopCtx = context.AfterFunc(ctx, func() { p.abandon(e) })
+	}
+	p.mu.Unlock()
+	p.start(started)
+	return nil
+}
+
+// enqueue inserts the entry after the entries of the same or a higher priority.
+func (p *Pool) enqueue(e *entry) {
+	i := len(p.pending)
+	for i > 0 && p.pending[i-1].job.Priority < e.job.Priority {
+		i--
+	}
+	p.pending = append(p.pending, nil)
+	copy(p.pending[i+1:], p.pending[i:])
+	p.pending[i] = e
+}
+
+// dispatch dequeues the jobs that can start, to be started once the lock is released.
+func (p *Pool) dispatch() []*entry {
+	var started []*entry
+	for i := 0; i < len(p.pending) && len(p.running) < p.workers; {
+		e := p.pending[i]
+		if limit, ok := p.skillLimits[e.job.Skill]; ok && p.skills[e.job.Skill] >= limit {
+			i++ // Lets the next jobs overtake it, while the skill is busy
+			continue
+		}
+		p.pending = append(p.pending[:i], p.pending[i+1:]...)
+		e.info.StartedAt = p.now()
+		p.running[e] = struct{}{}
+		p.skills[e.job.Skill]++
+		started = append(started, e)
+	}
+	return started
+}
+
+func (p *Pool) start(started []*entry) {
+	for _, e := range started {
+		if e.stopCtx != nil {
+			e.stopCtx()
+		}
+		go func() {
+			defer p.done(e)
+			e.job.Run(e.ctx)
+		}()
+	}
+}
+
+// done frees the worker of the job, and starts the next jobs.
+func (p *Pool) done(e *entry) {
+	p.mu.Lock()
+	delete(p.running, e)
+	if p.skills[e.job.Skill]--; p.skills[e.job.Skill] <= 0 {
+		delete(p.skills, e.job.Skill)
+	}
+	started := p.dispatch()
+	p.mu.Unlock()
+	p.start(started)
+}
+
+// abandon dequeues the job whose context is done, and runs it so that it releases its resources.
+func (p *Pool) abandon(e *entry) {
+	p.mu.Lock()
+	i := slices.Index(p.pending, e)
+	if i >= 0 {
+		p.pending = append(p.pending[:i], p.pending[i+1:]...)
+	}
+	p.mu.Unlock()
+	if i >= 0 {
+		go e.job.Run(e.ctx)
+	}
+}
+
+// Queued returns the queued jobs, in the order they will be picked up if their skills allow it.
+func (p *Pool) Queued() []JobInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	infos := make([]JobInfo, 0, len(p.pending))
+	for _, e := range p.pending {
+		infos = append(infos, e.info)
+	}
+	return infos
+}
+
+// Running returns the running jobs, by start time.
+func (p *Pool) Running() []JobInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	infos := make([]JobInfo, 0, len(p.running))
+	for e := range p.running {
+		infos = append(infos, e.info)
+	}
+	slices.SortFunc(infos, func(a, b JobInfo) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return infos
+}
+
+// WithMaxQueue sets how many jobs may wait, Schedule returning ErrQueueFull beyond.
+// By default, the queue is unbounded.
+func WithMaxQueue(size int) PoolOption {
+	return PoolOptionFunc(func(p *Pool) {
+		p.maxQueue = size
+	})
+}
+
+// WithSkillLimit sets how many jobs of the skill may run at once, within the workers of the pool.
+// The limit is advisory: the skill of a job is the one its client names in the MetadataSkill
+// metadata, so a client can escape the limit by naming another skill or none. It only bounds
+// the clients that cooperate; the size of the pool bounds the others.
+func WithSkillLimit(skill string, limit int) PoolOption {
+	return PoolOptionFunc(func(p *Pool) {
+		p.skillLimits[skill] = limit
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/server/event"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+// testJobs runs jobs that report when they start and wait to be released.
+type testJobs struct {
+	started chan string
+	release map[string]chan struct{}
+}
+
+func newTestJobs() *testJobs {
+	return &testJobs{started: make(chan string, 10), release: make(map[string]chan struct{})}
+}
+
+func (j *testJobs) job(taskId string, skill string, priority int) Job {
+	release := make(chan struct{})
+	j.release[taskId] = release
+	return Job{
+		TaskId:   taskId,
+		Skill:    skill,
+		Priority: priority,
+		Queue:    event.NewQueue(10),
+		Run: func(ctx context.Context) {
+			j.started <- taskId
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+		},
+	}
+}
+
+func (j *testJobs) next(t *testing.T) string {
+	select {
+	case taskId := <-j.started:
+		return taskId
+	case <-time.After(time.Second):
+		t.Fatal("no job started")
+		return ""
+	}
+}
+
+func (j *testJobs) none(t *testing.T) {
+	select {
+	case taskId := <-j.started:
+		t.Fatalf("job %s started", taskId)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func taskIds(infos []JobInfo) []string {
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.TaskId)
+	}
+	return ids
+}
+
+func TestPoolPriority(t *testing.T) {
+	jobs := newTestJobs()
+	pool := NewPool(1)
+	ctx := context.Background()
+
+	running := jobs.job("running", "", 0)
+	require.NoError(t, pool.Schedule(ctx, running))
+	assert.Equal(t, "running", jobs.next(t))
+
+	low := jobs.job("low", "", 0)
+	require.NoError(t, pool.Schedule(ctx, low))
+	require.NoError(t, pool.Schedule(ctx, jobs.job("high", "", 5)))
+	require.NoError(t, pool.Schedule(ctx, jobs.job("low2", "", 0)))
+	jobs.none(t)
+	assert.Equal(t, []string{"running"}, taskIds(pool.Running()))
+	assert.Equal(t, []string{"high", "low", "low2"}, taskIds(pool.Queued()))
+
+	e := <-low.Queue.Subscribe(ctx)
+	require.Equal(t, types.EventData, e.Type)
+	update, ok := e.Event.(*types.TaskStatusUpdateEvent)
+	require.True(t, ok)
+	assert.Equal(t, types.SUBMITTED, update.Status.State, "queued tasks report SUBMITTED")
+
+	close(jobs.release["running"])
+	assert.Equal(t, "high", jobs.next(t))
+	close(jobs.release["high"])
+	assert.Equal(t, "low", jobs.next(t))
+	close(jobs.release["low"])
+	assert.Equal(t, "low2", jobs.next(t))
+	close(jobs.release["low2"])
+}
+
+func TestPoolSkillLimit(t *testing.T) {
+	jobs := newTestJobs()
+	pool := NewPool(2, WithSkillLimit("gpu", 1))
+	ctx := context.Background()
+
+	require.NoError(t, pool.Schedule(ctx, jobs.job("gpu1", "gpu", 0)))
+	assert.Equal(t, "gpu1", jobs.next(t))
+	require.NoError(t, pool.Schedule(ctx, jobs.job("gpu2", "gpu", 0)))
+	jobs.none(t)
+	require.NoError(t, pool.Schedule(ctx, jobs.job("cpu", "", 0)))
+	assert.Equal(t, "cpu", jobs.next(t), "overtakes the job waiting for its skill")
+	assert.Equal(t, []string{"gpu2"}, taskIds(pool.Queued()))
+
+	close(jobs.release["gpu1"])
+	assert.Equal(t, "gpu2", jobs.next(t))
+	close(jobs.release["gpu2"])
+	close(jobs.release["cpu"])
+}
+
+func TestPoolQueue(t *testing.T) {
+	jobs := newTestJobs()
+	pool := NewPool(1, WithMaxQueue(1))
+
+	require.NoError(t, pool.Schedule(context.Background(), jobs.job("running", "", 0)))
+	assert.Equal(t, "running", jobs.next(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, pool.Schedule(ctx, jobs.job("canceled", "", 0)))
+	assert.ErrorIs(t, pool.Schedule(context.Background(), jobs.job("rejected", "", 0)), ErrQueueFull)
+
+	cancel()
+	assert.Equal(t, "canceled", jobs.next(t), "run once its context is done, to release its resources")
+	assert.Empty(t, pool.Queued())
+	assert.Equal(t, []string{"running"}, taskIds(pool.Running()))
+	close(jobs.release["running"])
+	require.Eventually(t, func() bool { return len(pool.Running()) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestNewJob(t *testing.T) {
+	testcases := []struct {
+		name         string
+		params       types.MessageSendParam
+		wantSkill    string
+		wantPriority int
+	}{
+		{name: "no metadata", params: types.MessageSendParam{Message: &types.Message{}}},
+		{
+			name:         "message metadata",
+			params:       types.MessageSendParam{Message: &types.Message{Metadata: map[string]any{"priority": float64(3), "skill": "summarize"}}},
+			wantSkill:    "summarize",
+			wantPriority: 3,
+		},
+		{
+			name: "message metadata takes precedence",
+			params: types.MessageSendParam{
+				Metadata: map[string]any{"priority": "7", "skill": "translate"},
+				Message:  &types.Message{Metadata: map[string]any{"priority": "-1"}},
+			},
+			wantSkill:    "translate",
+			wantPriority: -1,
+		},
+		{name: "invalid priority", params: types.MessageSendParam{Metadata: map[string]any{"priority": "high"}}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := NewJob("1", "2", tc.params, nil, func(ctx context.Context) {})
+			assert.Equal(t, tc.wantSkill, job.Skill)
+			assert.Equal(t, tc.wantPriority, job.Priority)
+		})
+	}
+}
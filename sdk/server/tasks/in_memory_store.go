@@ -35,7 +35,7 @@ func NewInMemoryTaskStore() *InMemoryTaskStore {
 func (s *InMemoryTaskStore) Save(ctx context.Context, task *types.Task) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.tasks[task.GetTaskId()] = task
+	s.tasks[task.GetTaskId()] = CloneTask(task)
 	return nil
 }
 
@@ -43,7 +43,7 @@ func (s *InMemoryTaskStore) Get(ctx context.Context, taskID string) (*types.Task
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if task, exists := s.tasks[taskID]; exists {
-		return task, nil
+		return CloneTask(task), nil
 	}
 	return nil, nil
 }
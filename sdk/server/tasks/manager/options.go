@@ -47,3 +47,10 @@ func WithInitMessage(message *types.Message) TaskManagerOption {
 		manger.initMessage = message
 	})
 }
+
+// WithOwner sets the name of the user on whose behalf the task is created.
+func WithOwner(owner string) TaskManagerOption {
+	return TaskManagerOptionFunc(func(manger *TaskManager) {
+		manger.owner = owner
+	})
+}
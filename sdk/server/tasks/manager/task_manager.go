@@ -31,6 +31,7 @@ type TaskManager struct {
 	store       tasks.TaskStore // Task storage backend
 	initMessage *types.Message  // Initial message for the task
 	currentTask *types.Task     // Cached current task
+	owner       string          // Name of the user creating the task
 }
 
 // NewTaskManager creates a new TaskManager with the given store and options.
@@ -89,6 +90,11 @@ func (t *TaskManager) handleTaskEvent(ctx context.Context, event types.Event) (*
 	if !ok {
 		return nil, errors.New("invalid event type for task event")
 	}
+	owner := TaskOwner(t.currentTask)
+	if owner == "" {
+		owner = t.owner
+	}
+	setOwner(task, owner)
 	if err := t.saveTask(ctx, task); err != nil {
 		return nil, err
 	}
@@ -233,5 +239,22 @@ func (t *TaskManager) newTask(taskId string, contextId string) *types.Task {
 	if t.initMessage != nil {
 		task.History = append(task.History, t.initMessage)
 	}
+	setOwner(task, t.owner)
 	return task
 }
+
+// TaskOwner returns the name of the user who created the task, or an empty string if it is not recorded.
+func TaskOwner(task *types.Task) string {
+	if task == nil {
+		return ""
+	}
+	return task.Owner
+}
+
+// setOwner records the owner of the task, outside of its metadata, which clients can see and set.
+func setOwner(task *types.Task, owner string) {
+	if owner == "" {
+		return
+	}
+	task.Owner = owner
+}
@@ -351,3 +351,52 @@ func TestSaveTaskEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskOwner(t *testing.T) {
+	testCases := []struct {
+		name    string
+		current *types.Task
+		event   types.Event
+		want    string
+	}{
+		{
+			name:  "new task records the owner",
+			event: &types.TaskStatusUpdateEvent{TaskId: "1", ContextId: "2", Kind: types.EventTypeStatusUpdate},
+			want:  "alice",
+		},
+		{
+			name:  "task event records the owner",
+			event: &types.Task{Id: "1", ContextId: "2"},
+			want:  "alice",
+		},
+		{
+			name:    "task event keeps the recorded owner",
+			current: &types.Task{Id: "1", ContextId: "2", Owner: "bob"},
+			event:   &types.Task{Id: "1", ContextId: "2", Owner: "mallory"},
+			want:    "bob",
+		},
+		{
+			name:    "metadata does not change the owner",
+			current: &types.Task{Id: "1", ContextId: "2", Owner: "bob"},
+			event: &types.TaskStatusUpdateEvent{TaskId: "1", ContextId: "2", Kind: types.EventTypeStatusUpdate,
+				Metadata: map[string]any{"a2a.owner": "mallory", "owner": "mallory"}},
+			want: "bob",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mocktasks.NewMockTaskStore(ctrl)
+			store.EXPECT().Get(gomock.Any(), "1").Return(tc.current, nil).AnyTimes()
+			store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+			manager := NewTaskManager(store, WithTaskId("1"), WithContextId("2"), WithOwner("alice"))
+			_, err := manager.GetTask(context.Background())
+			require.NoError(t, err)
+			task, err := manager.SaveTaskEvent(context.Background(), tc.event)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, TaskOwner(task))
+		})
+	}
+}
@@ -16,11 +16,17 @@ package tasks
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
 
 	"github.com/yeeaiclub/a2a-go/sdk/types"
 )
 
-// TaskStore Agent task store interface
+// TaskStore Agent task store interface. Stores must keep the Owner of the tasks, which is
+// not part of their JSON encoding: a task losing its owner can be accessed by any user.
+// Stores serializing tasks as JSON encode them with MarshalTask and decode them with UnmarshalTask.
 type TaskStore interface {
 	// Save or updates a task in the store.
 	Save(ctx context.Context, task *types.Task) error
@@ -29,3 +35,37 @@ type TaskStore interface {
 	// Delete a tasks from the store by id
 	Delete(ctx context.Context, id string) error
 }
+
+// CloneTask copies the task, so that updating the copy does not race with the readers of the
+// task. Messages and artifacts are shared, they are not updated in place.
+func CloneTask(task *types.Task) *types.Task {
+	clone := *task
+	clone.History = slices.Clone(task.History)
+	clone.Artifacts = slices.Clone(task.Artifacts)
+	clone.Metadata = maps.Clone(task.Metadata)
+	return &clone
+}
+
+// storedTask is the JSON encoding of a task in a store, with the fields hidden from clients.
+type storedTask struct {
+	Task  *types.Task `json:"task"`
+	Owner string      `json:"owner,omitempty"`
+}
+
+// MarshalTask encodes the task for a store, including its owner.
+func MarshalTask(task *types.Task) ([]byte, error) {
+	return json.Marshal(storedTask{Task: task, Owner: task.Owner})
+}
+
+// UnmarshalTask decodes a task encoded by MarshalTask, including its owner.
+func UnmarshalTask(data []byte) (*types.Task, error) {
+	var stored storedTask
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	if stored.Task == nil {
+		return nil, errors.New("stored task is empty")
+	}
+	stored.Task.Owner = stored.Owner
+	return stored.Task, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yeeaiclub/a2a-go/sdk/types"
+)
+
+func TestMarshalTask(t *testing.T) {
+	task := &types.Task{
+		Id:        "1",
+		ContextId: "2",
+		Status:    types.TaskStatus{State: types.WORKING},
+		Metadata:  map[string]any{"a2a.owner": "mallory"},
+		Artifacts: []types.Artifact{},
+		Owner:     "alice",
+	}
+
+	data, err := MarshalTask(task)
+	require.NoError(t, err)
+	stored, err := UnmarshalTask(data)
+	require.NoError(t, err)
+	assert.Equal(t, task, stored, "the owner survives a round trip through the store encoding")
+
+	data, err = json.Marshal(task)
+	require.NoError(t, err)
+	var sent types.Task
+	require.NoError(t, json.Unmarshal(data, &sent))
+	assert.Empty(t, sent.Owner, "the client encoding has no owner")
+
+	_, err = UnmarshalTask([]byte(`{"owner": "alice"}`))
+	assert.Error(t, err)
+}
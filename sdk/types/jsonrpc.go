@@ -58,6 +58,13 @@ func JSONParseError(err error) *JSONRPCError {
 	}
 }
 
+func InvalidRequestError(message string) *JSONRPCError {
+	return &JSONRPCError{
+		Code:    ErrorCodeInvalidRequest,
+		Message: message,
+	}
+}
+
 func MethodNotFoundError() *JSONRPCError {
 	return &JSONRPCError{
 		Code:    ErrorCodeMethodNotFound,
@@ -72,6 +79,34 @@ func InternalError() *JSONRPCError {
 	}
 }
 
+func PermissionDeniedError() *JSONRPCError {
+	return &JSONRPCError{
+		Code:    ErrorCodeInvalidRequest,
+		Message: "Permission denied",
+	}
+}
+
+func RateLimitedError() *JSONRPCError {
+	return &JSONRPCError{
+		Code:    ErrorCodeInvalidRequest,
+		Message: "Rate limit exceeded",
+	}
+}
+
+func TooManyTasksError() *JSONRPCError {
+	return &JSONRPCError{
+		Code:    ErrorCodeInvalidRequest,
+		Message: "Too many active tasks",
+	}
+}
+
+func ShuttingDownError() *JSONRPCError {
+	return &JSONRPCError{
+		Code:    ErrorCodeInternalError,
+		Message: "Server is shutting down",
+	}
+}
+
 func JSONRPCSuccessResponse(id string, result any) JSONRPCResponse {
 	return JSONRPCResponse{
 		Id:      id,
@@ -100,3 +135,15 @@ func MapTo[T any](result any) (T, error) {
 	}
 	return value, nil
 }
+
+// UnmarshalParams decodes the raw params of a request. Missing params decode to the zero value.
+func UnmarshalParams[T any](params json.RawMessage) (T, error) {
+	var value T
+	if len(params) == 0 {
+		return value, nil
+	}
+	if err := json.Unmarshal(params, &value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
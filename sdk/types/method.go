@@ -15,7 +15,8 @@
 package types
 
 const (
-	AgentCardPath = "/.well-known/agent.json"
+	AgentCardPath         = "/.well-known/agent.json"
+	ExtendedAgentCardPath = "/agent/authenticatedExtendedCard"
 )
 
 const (
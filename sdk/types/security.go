@@ -14,6 +14,11 @@
 
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type SecurityScheme interface {
 	GetType() string
 }
@@ -44,9 +49,9 @@ func (h HTTPAuthSecurityScheme) GetType() string {
 
 // OAuth2SecurityScheme OAuth Security scheme configuration
 type OAuth2SecurityScheme struct {
-	Description string `json:"description"`
-	Flows       any    `json:"flows"`
-	Type        string `json:"type"`
+	Description string     `json:"description"`
+	Flows       OAuthFlows `json:"flows"`
+	Type        string     `json:"type"`
 }
 
 func (h OAuth2SecurityScheme) GetType() string {
@@ -63,9 +68,22 @@ func (h OpenIdConnectSecurityScheme) GetType() string {
 	return OPENIDConnect
 }
 
+// MutualTLSSecurityScheme Mutual TLS security scheme, the client authenticating with a certificate.
+type MutualTLSSecurityScheme struct {
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+func (m MutualTLSSecurityScheme) GetType() string {
+	return MUTUALTLS
+}
+
+// OAuthFlows Allows configuration of the supported OAuth Flows, nil flows are not supported
 type OAuthFlows struct {
-	AuthorizationCode AuthorizationCodeOAuthFlow `json:"authorization_code,omitempty"`
-	ClientCredentials ClientCredentialsOAuthFlow `json:"client_credentials,omitempty"`
+	AuthorizationCode *AuthorizationCodeOAuthFlow `json:"authorization_code,omitempty"`
+	ClientCredentials *ClientCredentialsOAuthFlow `json:"client_credentials,omitempty"`
+	Implicit          *ImplicitOAuthFlow          `json:"implicit,omitempty"`
+	Password          *PasswordOAuthFlow          `json:"password,omitempty"`
 }
 
 type AuthorizationCodeOAuthFlow struct {
@@ -88,6 +106,13 @@ type ClientCredentialsOAuthFlow struct {
 	Scopes     map[string]string `json:"scopes,omitempty"`
 }
 
+// PasswordOAuthFlow Configuration details for the Resource Owner Password flow
+type PasswordOAuthFlow struct {
+	TokenUrl   string            `json:"token_url"`
+	RefreshUrl string            `json:"refresh_url,omitempty"`
+	Scopes     map[string]string `json:"scopes,omitempty"`
+}
+
 // In the location
 type In string
 
@@ -98,8 +123,78 @@ const (
 )
 
 const (
-	APIKEY        = "api_key"
+	APIKEY        = "apiKey"
 	HTTP          = "http"
 	OAUTH2        = "oauth2"
 	OPENIDConnect = "openIdConnect"
+	MUTUALTLS     = "mutualTLS"
+
+	// legacyAPIKey is the api key type written by earlier versions of this package.
+	legacyAPIKey = "api_key"
 )
+
+// withType returns the scheme with its Type field set from GetType.
+func withType(scheme SecurityScheme) SecurityScheme {
+	switch s := scheme.(type) {
+	case APIKeySecurityScheme:
+		s.Type = s.GetType()
+		return s
+	case HTTPAuthSecurityScheme:
+		s.Type = s.GetType()
+		return s
+	case OAuth2SecurityScheme:
+		s.Type = s.GetType()
+		return s
+	case OpenIdConnectSecurityScheme:
+		s.Type = s.GetType()
+		return s
+	case MutualTLSSecurityScheme:
+		s.Type = s.GetType()
+		return s
+	}
+	return scheme
+}
+
+// unmarshalSecurityScheme decodes a scheme into the concrete type named by its type field.
+func unmarshalSecurityScheme(data []byte) (SecurityScheme, error) {
+	var typeHolder struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &typeHolder); err != nil {
+		return nil, err
+	}
+
+	var scheme SecurityScheme
+	var err error
+	switch typeHolder.Type {
+	case APIKEY, legacyAPIKey:
+		var s APIKeySecurityScheme
+		err = json.Unmarshal(data, &s)
+		scheme = s
+	case HTTP:
+		var s HTTPAuthSecurityScheme
+		err = json.Unmarshal(data, &s)
+		scheme = s
+	case OAUTH2:
+		var s OAuth2SecurityScheme
+		err = json.Unmarshal(data, &s)
+		scheme = s
+	case OPENIDConnect:
+		var s OpenIdConnectSecurityScheme
+		err = json.Unmarshal(data, &s)
+		scheme = s
+	case MUTUALTLS:
+		var s MutualTLSSecurityScheme
+		err = json.Unmarshal(data, &s)
+		scheme = s
+	case "":
+		return nil, fmt.Errorf("security scheme has no type")
+	default:
+		return nil, fmt.Errorf("unknown security scheme type %q, expected one of %s, %s, %s, %s or %s",
+			typeHolder.Type, APIKEY, HTTP, OAUTH2, OPENIDConnect, MUTUALTLS)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withType(scheme), nil
+}
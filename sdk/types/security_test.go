@@ -0,0 +1,112 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentCardSecuritySchemesJSON(t *testing.T) {
+	card := AgentCard{
+		Name:     "agent",
+		Version:  "1.0.0",
+		Security: SecurityRequirement{{"apiKey": nil}, {"bearer": nil}},
+		SecuritySchemes: map[string]SecurityScheme{
+			"apiKey": APIKeySecurityScheme{In: InHeader, Name: "X-API-Key"},
+			"bearer": HTTPAuthSecurityScheme{Scheme: "bearer", BaseFormat: "JWT"},
+			"oauth": OAuth2SecurityScheme{Flows: OAuthFlows{ClientCredentials: &ClientCredentialsOAuthFlow{
+				TokenUrl: "https://auth.example.com/token",
+				Scopes:   map[string]string{"read": "read access"},
+			}}},
+			"oidc": OpenIdConnectSecurityScheme{OpenIdConnectUrl: "https://auth.example.com/.well-known/openid-configuration"},
+			"mtls": MutualTLSSecurityScheme{Description: "client certificate"},
+		},
+	}
+
+	data, err := json.Marshal(card)
+	require.NoError(t, err)
+
+	var raw struct {
+		SecuritySchemes map[string]struct {
+			Type string `json:"type"`
+		} `json:"security_schemes"`
+	}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	for name, want := range map[string]string{"apiKey": APIKEY, "bearer": HTTP, "oauth": OAUTH2, "oidc": OPENIDConnect, "mtls": MUTUALTLS} {
+		assert.Equal(t, want, raw.SecuritySchemes[name].Type, "type of %s is populated", name)
+	}
+
+	var decoded AgentCard
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	for name, scheme := range card.SecuritySchemes {
+		assert.Equal(t, withType(scheme), decoded.SecuritySchemes[name])
+	}
+	decoded.SecuritySchemes = card.SecuritySchemes
+	assert.Equal(t, card, decoded)
+}
+
+func TestAgentCardUnmarshalSecurityScheme(t *testing.T) {
+	testcases := []struct {
+		name    string
+		scheme  string
+		want    SecurityScheme
+		wantErr string
+	}{
+		{
+			name:   "api key",
+			scheme: `{"type": "apiKey", "in": "query", "name": "key"}`,
+			want:   APIKeySecurityScheme{Type: APIKEY, In: InQuery, Name: "key"},
+		},
+		{
+			name:   "legacy api key",
+			scheme: `{"type": "api_key", "in": "header", "name": "X-API-Key"}`,
+			want:   APIKeySecurityScheme{Type: APIKEY, In: InHeader, Name: "X-API-Key"},
+		},
+		{
+			name:   "mutual tls",
+			scheme: `{"type": "mutualTLS"}`,
+			want:   MutualTLSSecurityScheme{Type: MUTUALTLS},
+		},
+		{
+			name:    "unknown type",
+			scheme:  `{"type": "kerberos"}`,
+			wantErr: `failed to unmarshal security scheme "scheme": unknown security scheme type "kerberos"`,
+		},
+		{
+			name:    "missing type",
+			scheme:  `{"scheme": "bearer"}`,
+			wantErr: `failed to unmarshal security scheme "scheme": security scheme has no type`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := `{"name": "agent", "version": "1.0.0", "security_schemes": {"scheme": ` + tc.scheme + `}}`
+			var card AgentCard
+			err := json.Unmarshal([]byte(data), &card)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, card.SecuritySchemes["scheme"])
+		})
+	}
+}
@@ -98,6 +98,10 @@ type Task struct {
 	Status    TaskStatus     `json:"task_status,omitempty"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
 	Artifacts []Artifact     `json:"artifacts,omitempty"`
+
+	// Owner is the name of the user who created the task. It is never sent to clients,
+	// so a TaskStore serializing tasks must persist it separately, see tasks.MarshalTask.
+	Owner string `json:"-"`
 }
 
 func (t *Task) Done() bool {
@@ -14,6 +14,11 @@
 
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type SecurityRequirement []map[string][]string
 
 type AgentCard struct {
@@ -29,6 +34,62 @@ type AgentCard struct {
 	IconUrl            string                    `json:"icon_url,omitempty"`
 	Security           SecurityRequirement       `json:"security,omitempty"`
 	SecuritySchemes    map[string]SecurityScheme `json:"security_schemes,omitempty"`
+
+	SupportsAuthenticatedExtendedCard bool `json:"supports_authenticated_extended_card,omitempty"`
+}
+
+// RequiresMutualTLS reports whether the security requirement of the card references a mutualTLS scheme.
+func (c AgentCard) RequiresMutualTLS() bool {
+	for _, alternative := range c.Security {
+		for name := range alternative {
+			if _, ok := c.SecuritySchemes[name].(MutualTLSSecurityScheme); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MarshalJSON encodes the card, setting the type of every security scheme.
+func (c AgentCard) MarshalJSON() ([]byte, error) {
+	type Alias AgentCard // avoid recursion
+	aux := Alias(c)
+	if c.SecuritySchemes != nil {
+		aux.SecuritySchemes = make(map[string]SecurityScheme, len(c.SecuritySchemes))
+		for name, scheme := range c.SecuritySchemes {
+			aux.SecuritySchemes[name] = withType(scheme)
+		}
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes the card, decoding every security scheme into the concrete
+// type named by its type field. A scheme of an unknown type is an error.
+func (c *AgentCard) UnmarshalJSON(data []byte) error {
+	type Alias AgentCard // avoid recursion
+	aux := &struct {
+		SecuritySchemes map[string]json.RawMessage `json:"security_schemes,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(c),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.SecuritySchemes = nil
+	if aux.SecuritySchemes == nil {
+		return nil
+	}
+	c.SecuritySchemes = make(map[string]SecurityScheme, len(aux.SecuritySchemes))
+	for name, raw := range aux.SecuritySchemes {
+		scheme, err := unmarshalSecurityScheme(raw)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal security scheme %q: %w", name, err)
+		}
+		c.SecuritySchemes[name] = scheme
+	}
+	return nil
 }
 
 type AgentProvider struct {
@@ -73,7 +134,7 @@ type MessageSendParam struct {
 
 type MessageSendConfiguration struct {
 	AcceptedOutputModes    []string                `json:"accepted_output_modes,omitempty"`
-	Blocking               bool                    `json:"blocking,omitempty"`
+	Blocking               *bool                   `json:"blocking,omitempty"` // Waits for the task to finish if nil or true
 	HistoryLength          int                     `json:"history_length,omitempty"`
 	PushNotificationConfig *PushNotificationConfig `json:"push_notification_config,omitempty"`
 }
@@ -0,0 +1,273 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Severity is the severity of a Diagnostic.
+type Severity string
+
+const (
+	// SeverityError marks a problem that makes the card unusable by clients.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a problem that clients may work around.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in an agent card.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Field    string   `json:"field"` // Path of the field, such as skills[0].id
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Severity, d.Field, d.Message)
+}
+
+// Diagnostics are the problems found in an agent card.
+type Diagnostics []Diagnostic
+
+// HasErrors reports whether one of the diagnostics is an error.
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns a *ValidationError holding the diagnostics if one of them is an error, and nil otherwise.
+func (d Diagnostics) Err() error {
+	if !d.HasErrors() {
+		return nil
+	}
+	return &ValidationError{Diagnostics: d}
+}
+
+// ValidationError is returned for an agent card that has errors.
+type ValidationError struct {
+	Diagnostics Diagnostics
+}
+
+func (e *ValidationError) Error() string {
+	var messages []string
+	for _, diagnostic := range e.Diagnostics {
+		if diagnostic.Severity == SeverityError {
+			messages = append(messages, diagnostic.Field+": "+diagnostic.Message)
+		}
+	}
+	return "invalid agent card: " + strings.Join(messages, "; ")
+}
+
+// Validate checks that the card is well-formed and returns the problems found, if any.
+func (c AgentCard) Validate() Diagnostics {
+	v := &cardValidator{}
+	if c.Name == "" {
+		v.errorf("name", "is required")
+	}
+	if c.Version == "" {
+		v.errorf("version", "is required")
+	}
+	if c.URL == "" {
+		v.warnf("url", "is not set, clients cannot locate the agent endpoint")
+	} else {
+		v.checkURL("url", c.URL, SeverityError)
+	}
+	if c.IconUrl != "" {
+		v.checkURL("icon_url", c.IconUrl, SeverityWarning)
+	}
+	if c.Provider != nil && c.Provider.URL != "" {
+		v.checkURL("provider.url", c.Provider.URL, SeverityWarning)
+	}
+	v.checkModes("default_input_modes", c.DefaultInputModes)
+	v.checkModes("default_output_modes", c.DefaultOutputModes)
+	v.checkSkills(c.Skills)
+	v.checkSecurity(c.Security, c.SecuritySchemes)
+	return v.diagnostics
+}
+
+type cardValidator struct {
+	diagnostics Diagnostics
+}
+
+func (v *cardValidator) add(severity Severity, field, format string, args ...any) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{Severity: severity, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *cardValidator) errorf(field, format string, args ...any) {
+	v.add(SeverityError, field, format, args...)
+}
+
+func (v *cardValidator) warnf(field, format string, args ...any) {
+	v.add(SeverityWarning, field, format, args...)
+}
+
+func (v *cardValidator) checkURL(field, value string, severity Severity) {
+	u, err := url.Parse(value)
+	if err != nil {
+		v.add(severity, field, "is not a valid url: %v", err)
+		return
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		v.add(severity, field, "must be an absolute http or https url, got %q", value)
+		return
+	}
+	if u.Host == "" {
+		v.add(severity, field, "has no host")
+	}
+}
+
+func (v *cardValidator) checkModes(field string, modes []string) {
+	for i, mode := range modes {
+		mediaType, _, err := mime.ParseMediaType(mode)
+		if err != nil || !strings.Contains(mediaType, "/") {
+			v.errorf(fmt.Sprintf("%s[%d]", field, i), "%q is not a valid MIME type", mode)
+		}
+	}
+}
+
+func (v *cardValidator) checkSkills(skills []AgentSkill) {
+	if len(skills) == 0 {
+		v.warnf("skills", "no skill is declared")
+	}
+	seen := make(map[string]int, len(skills))
+	for i, skill := range skills {
+		field := fmt.Sprintf("skills[%d]", i)
+		switch previous, ok := seen[skill.ID]; {
+		case skill.ID == "":
+			v.errorf(field+".id", "is required")
+		case ok:
+			v.errorf(field+".id", "%q is also the id of skills[%d]", skill.ID, previous)
+		default:
+			seen[skill.ID] = i
+		}
+		if skill.Name == "" {
+			v.warnf(field+".name", "is not set")
+		}
+		v.checkModes(field+".input_modes", skill.InputModes)
+		v.checkModes(field+".output_modes", skill.OutputModes)
+	}
+}
+
+func (v *cardValidator) checkSecurity(security SecurityRequirement, schemes map[string]SecurityScheme) {
+	used := make(map[string]bool)
+	for i, alternative := range security {
+		names := make([]string, 0, len(alternative))
+		for name := range alternative {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			used[name] = true
+			field := fmt.Sprintf("security[%d].%s", i, name)
+			scheme, ok := schemes[name]
+			if !ok {
+				v.errorf(field, "references a scheme missing from security_schemes")
+				continue
+			}
+			if oauth2, ok := scheme.(OAuth2SecurityScheme); ok {
+				declared := oauth2.Flows.scopes()
+				for _, scope := range alternative[name] {
+					if !declared[scope] {
+						v.warnf(field, "scope %q is not declared by the flows of the scheme", scope)
+					}
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field := "security_schemes." + name
+		v.checkScheme(field, schemes[name])
+		if !used[name] {
+			v.warnf(field, "is not referenced by security")
+		}
+	}
+}
+
+func (v *cardValidator) checkScheme(field string, scheme SecurityScheme) {
+	switch s := scheme.(type) {
+	case nil:
+		v.errorf(field, "is null")
+	case APIKeySecurityScheme:
+		if s.Name == "" {
+			v.errorf(field+".name", "is required")
+		}
+		if s.In != InHeader && s.In != InQuery && s.In != InCookie {
+			v.errorf(field+".in", "must be header, query or cookie, got %q", s.In)
+		}
+	case HTTPAuthSecurityScheme:
+		if s.Scheme == "" {
+			v.errorf(field+".scheme", "is required")
+		}
+	case OAuth2SecurityScheme:
+		flows := s.Flows
+		if flows.AuthorizationCode == nil && flows.ClientCredentials == nil && flows.Implicit == nil && flows.Password == nil {
+			v.errorf(field+".flows", "declares no flow")
+		}
+		if f := flows.AuthorizationCode; f != nil {
+			v.checkURL(field+".flows.authorization_code.authorization_url", f.AuthorizationUrl, SeverityError)
+			v.checkURL(field+".flows.authorization_code.token_url", f.TokenUrl, SeverityError)
+		}
+		if f := flows.ClientCredentials; f != nil {
+			v.checkURL(field+".flows.client_credentials.token_url", f.TokenUrl, SeverityError)
+		}
+		if f := flows.Implicit; f != nil {
+			v.checkURL(field+".flows.implicit.authorization_url", f.AuthorizationUrl, SeverityError)
+		}
+		if f := flows.Password; f != nil {
+			v.checkURL(field+".flows.password.token_url", f.TokenUrl, SeverityError)
+		}
+	case OpenIdConnectSecurityScheme:
+		v.checkURL(field+".open_id_connect_url", s.OpenIdConnectUrl, SeverityError)
+	}
+}
+
+// scopes returns the scopes declared by all the flows.
+func (f OAuthFlows) scopes() map[string]bool {
+	scopes := make(map[string]bool)
+	var declared []map[string]string
+	if f.AuthorizationCode != nil {
+		declared = append(declared, f.AuthorizationCode.Scopes)
+	}
+	if f.ClientCredentials != nil {
+		declared = append(declared, f.ClientCredentials.Scopes)
+	}
+	if f.Implicit != nil {
+		declared = append(declared, f.Implicit.Scopes)
+	}
+	if f.Password != nil {
+		declared = append(declared, f.Password.Scopes)
+	}
+	for _, m := range declared {
+		for scope := range m {
+			scopes[scope] = true
+		}
+	}
+	return scopes
+}
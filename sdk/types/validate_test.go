@@ -0,0 +1,146 @@
+// Copyright 2025 yeeaiclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validCard() AgentCard {
+	return AgentCard{
+		Name:               "agent",
+		URL:                "https://agent.example.com/a2a",
+		Version:            "1.0.0",
+		DefaultInputModes:  []string{"text/plain"},
+		DefaultOutputModes: []string{"application/json; charset=utf-8"},
+		Skills:             []AgentSkill{{ID: "search", Name: "search"}},
+		Security:           SecurityRequirement{{"oauth": {"read"}}},
+		SecuritySchemes: map[string]SecurityScheme{
+			"oauth": OAuth2SecurityScheme{
+				Type: OAUTH2,
+				Flows: OAuthFlows{ClientCredentials: &ClientCredentialsOAuthFlow{
+					TokenUrl: "https://auth.example.com/token",
+					Scopes:   map[string]string{"read": "read access"},
+				}},
+			},
+		},
+	}
+}
+
+func TestAgentCardValidate(t *testing.T) {
+	testcases := []struct {
+		name   string
+		modify func(card *AgentCard)
+		want   Diagnostics
+	}{
+		{
+			name:   "valid card",
+			modify: func(card *AgentCard) {},
+		},
+		{
+			name: "missing required fields",
+			modify: func(card *AgentCard) {
+				card.Name = ""
+				card.Version = ""
+				card.URL = ""
+			},
+			want: Diagnostics{
+				{Severity: SeverityError, Field: "name", Message: "is required"},
+				{Severity: SeverityError, Field: "version", Message: "is required"},
+				{Severity: SeverityWarning, Field: "url", Message: "is not set, clients cannot locate the agent endpoint"},
+			},
+		},
+		{
+			name: "malformed url",
+			modify: func(card *AgentCard) {
+				card.URL = "agent.example.com"
+			},
+			want: Diagnostics{
+				{Severity: SeverityError, Field: "url", Message: `must be an absolute http or https url, got "agent.example.com"`},
+			},
+		},
+		{
+			name: "invalid mime type",
+			modify: func(card *AgentCard) {
+				card.DefaultInputModes = []string{"text/plain", "text"}
+			},
+			want: Diagnostics{
+				{Severity: SeverityError, Field: "default_input_modes[1]", Message: `"text" is not a valid MIME type`},
+			},
+		},
+		{
+			name: "invalid skills",
+			modify: func(card *AgentCard) {
+				card.Skills = []AgentSkill{{ID: "search", Name: "search"}, {Name: "summarize"}, {ID: "search"}}
+			},
+			want: Diagnostics{
+				{Severity: SeverityError, Field: "skills[1].id", Message: "is required"},
+				{Severity: SeverityError, Field: "skills[2].id", Message: `"search" is also the id of skills[0]`},
+				{Severity: SeverityWarning, Field: "skills[2].name", Message: "is not set"},
+			},
+		},
+		{
+			name: "undeclared scheme and scope",
+			modify: func(card *AgentCard) {
+				card.Security = SecurityRequirement{{"oauth": {"write"}}, {"apikey": nil}}
+			},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Field: "security[0].oauth", Message: `scope "write" is not declared by the flows of the scheme`},
+				{Severity: SeverityError, Field: "security[1].apikey", Message: "references a scheme missing from security_schemes"},
+			},
+		},
+		{
+			name: "invalid schemes",
+			modify: func(card *AgentCard) {
+				card.SecuritySchemes["apikey"] = APIKeySecurityScheme{Type: APIKEY, In: "body"}
+				card.SecuritySchemes["bearer"] = HTTPAuthSecurityScheme{Type: HTTP}
+				card.Security = SecurityRequirement{{"oauth": {"read"}, "apikey": nil}}
+			},
+			want: Diagnostics{
+				{Severity: SeverityError, Field: "security_schemes.apikey.name", Message: "is required"},
+				{Severity: SeverityError, Field: "security_schemes.apikey.in", Message: `must be header, query or cookie, got "body"`},
+				{Severity: SeverityError, Field: "security_schemes.bearer.scheme", Message: "is required"},
+				{Severity: SeverityWarning, Field: "security_schemes.bearer", Message: "is not referenced by security"},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			card := validCard()
+			tc.modify(&card)
+			diagnostics := card.Validate()
+			assert.Equal(t, tc.want, diagnostics)
+			assert.Equal(t, tc.want.HasErrors(), diagnostics.Err() != nil)
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	card := validCard()
+	card.Name = ""
+	card.URL = ""
+
+	err := card.Validate().Err()
+	require.Error(t, err)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Diagnostics, 2)
+	assert.Equal(t, "invalid agent card: name: is required", err.Error())
+}